@@ -0,0 +1,229 @@
+package ergo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// DefaultGapLimit is the number of consecutive unused addresses after which Discover stops, as recommended by EIP-3
+const DefaultGapLimit = 20
+
+// AddressUsed reports whether address has been used on the blockchain, e.g. by looking up its transactions
+// on a node or an explorer
+type AddressUsed func(address Address) (bool, error)
+
+// addressBook derives the addresses of an account and keeps track of the used ones, it implements the
+// address methods of Account and WatchOnlyAccount
+type addressBook struct {
+	mu sync.Mutex
+	// deriveAddress derives the address at index
+	deriveAddress func(index uint32) (Address, error)
+	// trees maps the base16 tree of every derived address to the address index
+	trees map[string]uint32
+	used  map[uint32]bool
+}
+
+func newAddressBook(deriveAddress func(index uint32) (Address, error)) addressBook {
+	return addressBook{
+		deriveAddress: deriveAddress,
+		trees:         make(map[string]uint32),
+		used:          make(map[uint32]bool),
+	}
+}
+
+// Account derives the addresses of an EIP-3 account, i.e. the addresses at the m/44'/429'/account'/0/index paths,
+// and keeps track of the used ones. An Account does not take ownership of the master key, the master key has to
+// stay open as long as the Account is used.
+// Addresses are derived with Address and NextAddress, MarkUsed, Used and Discover keep track of the used ones
+type Account struct {
+	addressBook
+	master ExtendedSecretKey
+	index  uint32
+}
+
+// NewAccount creates the Account with the account index derived from the master key, see DeriveMaster
+func NewAccount(master ExtendedSecretKey, index uint32) *Account {
+	a := &Account{master: master, index: index}
+	a.addressBook = newAddressBook(a.addressAt)
+	return a
+}
+
+// Index returns the account index
+func (a *Account) Index() uint32 {
+	return a.index
+}
+
+// Address returns the address at index
+func (b *addressBook) Address(index uint32) (Address, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.address(index)
+}
+
+// NextAddress returns the first address after the last used one and its index
+func (b *addressBook) NextAddress() (Address, uint32, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.nextAddress()
+}
+
+func (b *addressBook) nextAddress() (Address, uint32, error) {
+	var index uint32
+	for used := range b.used {
+		index = max(index, used+1)
+	}
+	address, err := b.address(index)
+	if err != nil {
+		return nil, 0, err
+	}
+	return address, index, nil
+}
+
+// MarkUsed marks the address at index as used
+func (b *addressBook) MarkUsed(index uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.used[index] = true
+}
+
+// Used returns the indices of the used addresses in ascending order
+func (b *addressBook) Used() []uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	indices := make([]uint32, 0, len(b.used))
+	for index := range b.used {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// Discover derives the addresses starting at index 0 and asks used whether they have been used, until gapLimit
+// consecutive addresses are unused. The used addresses are marked as used. used must not call methods of the account
+func (b *addressBook) Discover(gapLimit uint32, used AddressUsed) error {
+	if gapLimit == 0 {
+		return errors.New("gap limit must be greater than zero")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for index, unused := uint32(0), uint32(0); unused < gapLimit; index++ {
+		address, err := b.address(index)
+		if err != nil {
+			return err
+		}
+		isUsed, err := used(address)
+		address.Close()
+		if err != nil {
+			return err
+		}
+
+		if isUsed {
+			b.used[index] = true
+			unused = 0
+		} else {
+			unused++
+		}
+	}
+	return nil
+}
+
+// Wallet returns a Wallet holding exactly the secret keys of the addresses guarding boxes. Only the addresses
+// derived before, by Address, NextAddress or Discover, are considered, a box guarded by another address
+// makes Wallet fail
+func (a *Account) Wallet(boxes Boxes) (Wallet, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var indices []uint32
+	for _, box := range boxes.All() {
+		index, err := a.boxIndex(box)
+		box.Close()
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(indices, index) {
+			indices = append(indices, index)
+		}
+	}
+
+	secrets := NewSecretKeys()
+	defer secrets.Close()
+	for _, index := range indices {
+		key, err := a.derive(index)
+		if err != nil {
+			return nil, err
+		}
+		secret := key.SecretKey()
+		secrets.Add(secret)
+		secret.Close()
+		key.Destroy()
+	}
+	return NewWalletFromSecretKeys(secrets), nil
+}
+
+// boxIndex returns the index of the derived address guarding box
+func (b *addressBook) boxIndex(box Box) (uint32, error) {
+	ergoTree := box.Tree()
+	defer ergoTree.Close()
+	treeStr, err := ergoTree.Base16()
+	if err != nil {
+		return 0, err
+	}
+
+	index, ok := b.trees[treeStr]
+	if !ok {
+		boxId := box.BoxId()
+		defer boxId.Close()
+		return 0, fmt.Errorf("box %s is not guarded by a derived address of the account", boxId.Base16())
+	}
+	return index, nil
+}
+
+// derive derives the ExtendedSecretKey at index
+func (a *Account) derive(index uint32) (ExtendedSecretKey, error) {
+	path, err := NewDerivationPath(a.index, []uint32{index})
+	if err != nil {
+		return nil, err
+	}
+	defer path.Close()
+
+	return a.master.Derive(path)
+}
+
+// addressAt derives the address at index
+func (a *Account) addressAt(index uint32) (Address, error) {
+	key, err := a.derive(index)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Destroy()
+	publicKey := key.ExtendedPublicKey()
+	defer publicKey.Close()
+	return publicKey.Address(), nil
+}
+
+// address derives the address at index and remembers its tree
+func (b *addressBook) address(index uint32) (Address, error) {
+	address, err := b.deriveAddress(index)
+	if err != nil {
+		return nil, err
+	}
+	ergoTree := address.Tree()
+	defer ergoTree.Close()
+	treeStr, err := ergoTree.Base16()
+	if err != nil {
+		address.Close()
+		return nil, err
+	}
+
+	b.trees[treeStr] = index
+	return address, nil
+}
@@ -0,0 +1,100 @@
+package ergo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testAccount(t *testing.T) *Account {
+	seed := MnemonicToSeed("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "")
+	master, err := DeriveMaster(seed)
+	assert.NoError(t, err)
+	return NewAccount(master, 0)
+}
+
+func testAccountBox(t *testing.T, address Address) Box {
+	boxValue, _ := NewBoxValue(1000000000)
+	txId, _ := NewTxId("9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9")
+	contract, err := NewContractPayToAddress(address)
+	assert.NoError(t, err)
+
+	box, err := NewBox(boxValue, 284761, contract, txId, 0, NewTokens())
+	assert.NoError(t, err)
+	return box
+}
+
+func TestAccount_Discover(t *testing.T) {
+	account := testAccount(t)
+
+	first, err := account.Address(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r", first.Base58(MainnetPrefix))
+
+	var asked []string
+	err = account.Discover(3, func(address Address) (bool, error) {
+		asked = append(asked, address.Base58(MainnetPrefix))
+		// only the second address has been used
+		return address.Base58(MainnetPrefix) == "9gYRhhA9TcFv6xWGwTBPLBJzyW1Hv3EiDzXqoivWYjq8TowWJ1h", nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, asked, 5)
+	assert.Equal(t, []uint32{1}, account.Used())
+
+	next, index, err := account.NextAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), index)
+	assert.Equal(t, asked[2], next.Base58(MainnetPrefix))
+
+	account.MarkUsed(7)
+	_, index, _ = account.NextAddress()
+	assert.Equal(t, uint32(8), index)
+
+	assert.Error(t, account.Discover(0, nil))
+}
+
+func TestAccount_Wallet(t *testing.T) {
+	account := testAccount(t)
+
+	first, err := account.Address(0)
+	assert.NoError(t, err)
+	second, err := account.Address(1)
+	assert.NoError(t, err)
+
+	// spend a box of the second and one of the first address
+	boxes := NewBoxes()
+	boxes.Add(testAccountBox(t, second))
+	boxValue, _ := NewBoxValue(1000000000)
+	txId, _ := NewTxId("9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9")
+	contract, _ := NewContractPayToAddress(first)
+	firstBox, err := NewBox(boxValue, 284761, contract, txId, 1, NewTokens())
+	assert.NoError(t, err)
+	boxes.Add(firstBox)
+	w, err := account.Wallet(boxes)
+	assert.NoError(t, err)
+
+	recipient, _ := NewAddress("9gHMTduN2xseqb5NMKQtNSeS7Pe6wm7AwGoLoMERidWDERQunvn")
+	recipientContract, _ := NewContractPayToAddress(recipient)
+	outBoxValue, _ := NewBoxValue(1500000000)
+	outbox, _ := NewBoxCandidateBuilder(outBoxValue, recipientContract, 284762).Build()
+	txOutputs := NewBoxCandidates()
+	txOutputs.Add(outbox)
+	fee := SuggestedTxFee()
+	targetBalance, _ := SumOfBoxValues(outBoxValue, fee)
+	boxSelection, err := NewSimpleBoxSelector().Select(boxes, targetBalance, NewTokens())
+	assert.NoError(t, err)
+	unsignedTx, err := NewTxBuilder(boxSelection, txOutputs, 284762, fee, second).Build()
+	assert.NoError(t, err)
+
+	blockHeaders := testBlockHeadersFromJson()
+	blockHeader, _ := blockHeaders.Get(0)
+	stateContext, _ := NewStateContext(NewPreHeader(blockHeader), blockHeaders, DefaultParameters())
+	dataBoxes := NewBoxes()
+	signedTx, err := w.SignTransaction(stateContext, unsignedTx, boxes, dataBoxes)
+	assert.NoError(t, err)
+	assert.NoError(t, signedTx.Validate(stateContext, boxes, dataBoxes))
+
+	foreign, _ := NewAddress("9gHMTduN2xseqb5NMKQtNSeS7Pe6wm7AwGoLoMERidWDERQunvn")
+	boxes.Add(testAccountBox(t, foreign))
+	_, err = account.Wallet(boxes)
+	assert.ErrorContains(t, err, "is not guarded by a derived address")
+}
@@ -5,15 +5,20 @@ package ergo
 */
 import "C"
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"runtime"
 	"unsafe"
 )
 
-type networkPrefix uint8
+// NetworkPrefix is the network an Address is encoded for
+type NetworkPrefix uint8
 
 const (
 	// MainnetPrefix is the network prefix used in mainnet address encoding
-	MainnetPrefix networkPrefix = 0
+	MainnetPrefix NetworkPrefix = 0
 	// TestnetPrefix is the network prefix used in testnet address encoding
 	TestnetPrefix = 16
 )
@@ -30,8 +35,8 @@ const (
 )
 
 type Address interface {
-	// Base58 converts an Address to a base58 string using the provided networkPrefix.
-	Base58(prefix networkPrefix) string
+	// Base58 converts an Address to a base58 string using the provided NetworkPrefix.
+	Base58(prefix NetworkPrefix) string
 	// TypePrefix returns the addressTypePrefix for the Address.
 	// 0x01 - Pay-to-PublicKey(P2PK) address.
 	// 0x02 - Pay-to-Script-Hash(P2SH).
@@ -39,6 +44,8 @@ type Address interface {
 	TypePrefix() addressTypePrefix
 	// Tree returns the Address as Tree
 	Tree() Tree
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.AddressPtr
 }
 
@@ -97,7 +104,11 @@ func NewAddressFromPublicKey(publicKey []byte) (Address, error) {
 	return newAddress(a), nil
 }
 
-func (a *address) Base58(prefix networkPrefix) string {
+func (a *address) Base58(prefix NetworkPrefix) string {
+	if a.p == nil {
+		panic(ErrClosed)
+	}
+
 	var outAddrStr *C.char
 	cPrefix := C.uchar(prefix)
 
@@ -108,11 +119,19 @@ func (a *address) Base58(prefix networkPrefix) string {
 }
 
 func (a *address) TypePrefix() addressTypePrefix {
+	if a.p == nil {
+		panic(ErrClosed)
+	}
+
 	prefix := C.ergo_lib_address_type_prefix(a.p)
 	return addressTypePrefix(prefix)
 }
 
 func (a *address) Tree() Tree {
+	if a.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoTreePtr
 	C.ergo_lib_address_to_ergo_tree(a.p, &p)
 	t := &tree{p: p}
@@ -120,9 +139,131 @@ func (a *address) Tree() Tree {
 }
 
 func (a *address) pointer() C.AddressPtr {
+	if a.p == nil {
+		panic(ErrClosed)
+	}
+
 	return a.p
 }
 
+func (a *address) Close() {
+	if a.p != nil {
+		runtime.SetFinalizer(a, nil)
+		finalizeAddress(a)
+		a.p = nil
+	}
+}
+
 func finalizeAddress(a *address) {
 	C.ergo_lib_address_delete(a.p)
 }
+
+// NetworkAddress is an Address together with the network it is encoded for. Unlike Address it can be
+// encoded to and decoded from JSON, text and database columns, its zero value can be used as destination
+// for json.Unmarshal and sql.Scanner
+type NetworkAddress struct {
+	Network NetworkPrefix
+	Address Address
+}
+
+// NewNetworkAddress creates a NetworkAddress from a base58 string and detects the network it is encoded for
+func NewNetworkAddress(s string) (NetworkAddress, error) {
+	addressStr := C.CString(s)
+	defer C.free(unsafe.Pointer(addressStr))
+
+	var p C.AddressPtr
+
+	errPtr := C.ergo_lib_address_from_mainnet(addressStr, &p)
+	err := newError(errPtr)
+	if !err.isError() {
+		return NetworkAddress{Network: MainnetPrefix, Address: newAddress(&address{p})}, nil
+	}
+
+	errPtr = C.ergo_lib_address_from_testnet(addressStr, &p)
+	testnetErr := newError(errPtr)
+	if testnetErr.isError() {
+		return NetworkAddress{}, err.error()
+	}
+
+	return NetworkAddress{Network: TestnetPrefix, Address: newAddress(&address{p})}, nil
+}
+
+// String returns the NetworkAddress encoded as base58 string or an empty string if Address is nil
+func (n NetworkAddress) String() string {
+	if n.Address == nil {
+		return ""
+	}
+	return n.Address.Base58(n.Network)
+}
+
+func (n NetworkAddress) MarshalJSON() ([]byte, error) {
+	if n.Address == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.String())
+}
+
+func (n *NetworkAddress) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*n = NetworkAddress{}
+		return nil
+	}
+	return n.UnmarshalText([]byte(*s))
+}
+
+func (n NetworkAddress) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+func (n *NetworkAddress) UnmarshalText(text []byte) error {
+	a, err := NewNetworkAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*n = a
+	return nil
+}
+
+// Scan implements sql.Scanner, a NULL column results in a NetworkAddress with nil Address
+func (n *NetworkAddress) Scan(src any) error {
+	switch s := src.(type) {
+	case nil:
+		*n = NetworkAddress{}
+		return nil
+	case string:
+		return n.UnmarshalText([]byte(s))
+	case []byte:
+		return n.UnmarshalText(s)
+	default:
+		return fmt.Errorf("ergo: cannot scan %T into NetworkAddress", src)
+	}
+}
+
+// Value implements driver.Valuer, a nil Address is stored as NULL
+func (n NetworkAddress) Value() (driver.Value, error) {
+	if n.Address == nil {
+		return nil, nil
+	}
+	return n.String(), nil
+}
+
+// p2pkProposition returns the serialized ProveDlog of a P2PK address as expected by Propositions
+func p2pkProposition(address Address) ([]byte, error) {
+	if address.TypePrefix() != P2PkPrefix {
+		return nil, errors.New("address is not a P2PK address")
+	}
+
+	ergoTree := address.Tree()
+	defer ergoTree.Close()
+	treeBytes, err := decodeBase16(ergoTree.Base16())
+	if err != nil {
+		return nil, err
+	}
+
+	// the tree consists of the header and the SigmaProp constant type followed by the ProveDlog
+	return treeBytes[2:], nil
+}
@@ -1,6 +1,7 @@
 package ergo
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -43,3 +44,29 @@ func TestAddress_Tree(t *testing.T) {
 	assert.NoError(t, treeErr)
 	assert.Equal(t, addr.Base58(MainnetPrefix), testAddr.Base58(MainnetPrefix))
 }
+
+func TestNetworkAddress_JSON(t *testing.T) {
+	var addresses []NetworkAddress
+	err := json.Unmarshal([]byte(`["9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ","3WwqxmeXRWpfaH9YMLQFye7Y6ddsg1anS9hFN2EQs1P6uNMjt9tK",null]`), &addresses)
+	assert.NoError(t, err)
+
+	assert.Equal(t, MainnetPrefix, addresses[0].Network)
+	assert.Equal(t, NetworkPrefix(TestnetPrefix), addresses[1].Network)
+	assert.Nil(t, addresses[2].Address)
+
+	encoded, err := json.Marshal(addresses)
+	assert.NoError(t, err)
+	assert.Equal(t, `["9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ","3WwqxmeXRWpfaH9YMLQFye7Y6ddsg1anS9hFN2EQs1P6uNMjt9tK",null]`, string(encoded))
+}
+
+func TestNetworkAddress_Scan(t *testing.T) {
+	var addr NetworkAddress
+	assert.NoError(t, addr.Scan([]byte("3WwqxmeXRWpfaH9YMLQFye7Y6ddsg1anS9hFN2EQs1P6uNMjt9tK")))
+
+	value, err := addr.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "3WwqxmeXRWpfaH9YMLQFye7Y6ddsg1anS9hFN2EQs1P6uNMjt9tK", value)
+
+	assert.Error(t, addr.Scan("invalid,"))
+	assert.Error(t, addr.Scan(42))
+}
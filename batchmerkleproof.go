@@ -11,6 +11,8 @@ import (
 
 type BatchMerkleProof interface {
 	Valid(expectedRoot []byte) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type batchMerkleProof struct {
@@ -37,12 +39,24 @@ func NewBatchMerkleProof(json string) (BatchMerkleProof, error) {
 }
 
 func (b *batchMerkleProof) Valid(expectedRoot []byte) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	byteData := C.CBytes(expectedRoot)
 	defer C.free(unsafe.Pointer(byteData))
 	res := C.ergo_lib_batch_merkle_proof_valid(b.p, (*C.uchar)(byteData), C.uintptr_t(len(expectedRoot)))
 	return bool(res)
 }
 
+func (b *batchMerkleProof) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBatchMerkleProof(b)
+		b.p = nil
+	}
+}
+
 func finalizeBatchMerkleProof(b *batchMerkleProof) {
 	C.ergo_lib_batch_merkle_proof_delete(b.p)
 }
@@ -0,0 +1,190 @@
+package ergo
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ripemd160"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ergo-lib-c does not expose the chain code of extended keys, it is tracked on the Go side following BIP-32.
+// The elliptic curve operations are left to ergo-lib-c, the chain codes only need HMAC-SHA512
+
+const hardenedIndex = 0x80000000
+
+// version bytes of serialized extended public keys as used by BIP-32
+const (
+	xpubVersion = 0x0488b21e
+	tpubVersion = 0x043587cf
+)
+
+const (
+	extendedKeyLength = 78
+	chainCodeLength   = 32
+)
+
+// masterChainCode returns the chain code of the master key derived from seed
+func masterChainCode(seed []byte) []byte {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	return mac.Sum(nil)[32:]
+}
+
+// childChainCode returns the chain code of the child at index. parentKey is the secret key of the parent
+// for hardened indices and the compressed public key of the parent otherwise
+func childChainCode(chainCode []byte, parentKey []byte, index uint32) []byte {
+	mac := hmac.New(sha512.New, chainCode)
+	if index >= hardenedIndex {
+		mac.Write([]byte{0})
+	}
+	mac.Write(parentKey)
+	mac.Write(binary.BigEndian.AppendUint32(nil, index))
+	return mac.Sum(nil)[32:]
+}
+
+// fingerprint returns the fingerprint of a compressed public key, the first 4 bytes of its HASH160
+func fingerprint(publicKey []byte) uint32 {
+	sha := sha256.Sum256(publicKey)
+	h := ripemd160.New()
+	h.Write(sha[:])
+	return binary.BigEndian.Uint32(h.Sum(nil))
+}
+
+// parseChildIndex parses a soft or hardened child index, e.g. 4 or 4'
+func parseChildIndex(s string) (uint32, error) {
+	hardened := strings.HasSuffix(s, "'")
+	index, err := strconv.ParseUint(strings.TrimSuffix(s, "'"), 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("invalid child index %q", s)
+	}
+	if hardened {
+		index += hardenedIndex
+	}
+	return uint32(index), nil
+}
+
+func formatChildIndex(index uint32) string {
+	if index >= hardenedIndex {
+		return strconv.FormatUint(uint64(index-hardenedIndex), 10) + "'"
+	}
+	return strconv.FormatUint(uint64(index), 10)
+}
+
+// parseDerivationPath parses a derivation path in the m/44'/429'/0'/0/0 format. ergo-lib-c formats the
+// path of a master key as m/, a trailing slash is accepted
+func parseDerivationPath(s string) ([]uint32, error) {
+	elements := strings.Split(strings.TrimSuffix(s, "/"), "/")
+	if elements[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q", s)
+	}
+	indices := make([]uint32, 0, len(elements)-1)
+	for _, element := range elements[1:] {
+		index, err := parseChildIndex(element)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+func formatDerivationPath(indices []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range indices {
+		sb.WriteString("/")
+		sb.WriteString(formatChildIndex(index))
+	}
+	return sb.String()
+}
+
+// childPath returns the indices of path following parent, it fails if parent is not a prefix of path
+func childPath(parent []uint32, path []uint32) ([]uint32, error) {
+	if len(path) < len(parent) || !slices.Equal(parent, path[:len(parent)]) {
+		return nil, errors.New("derivation path does not extend the path of the key")
+	}
+	return path[len(parent):], nil
+}
+
+// encodeExtendedPublicKey serializes an extended public key as defined by BIP-32
+func encodeExtendedPublicKey(version uint32, path []uint32, parentFingerprint uint32, chainCode []byte, publicKey []byte) string {
+	data := make([]byte, 0, extendedKeyLength)
+	data = binary.BigEndian.AppendUint32(data, version)
+	data = append(data, byte(len(path)))
+	data = binary.BigEndian.AppendUint32(data, parentFingerprint)
+	var childNumber uint32
+	if len(path) > 0 {
+		childNumber = path[len(path)-1]
+	}
+	data = binary.BigEndian.AppendUint32(data, childNumber)
+	data = append(data, chainCode...)
+	data = append(data, publicKey...)
+	return base58CheckEncode(data)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func base58CheckEncode(data []byte) string {
+	checksum := doubleSha256(data)
+	data = append(data[:len(data):len(data)], checksum[:4]...)
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var encoded []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+func base58CheckDecode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	zeros := 0
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base58Alphabet, s[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+		if digit == 0 && zeros == i {
+			zeros++
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	data := append(make([]byte, zeros), n.Bytes()...)
+	if len(data) < 4 {
+		return nil, errors.New("base58 data is too short")
+	}
+	data, checksum := data[:len(data)-4], data[len(data)-4:]
+	expected := doubleSha256(data)
+	if !bytes.Equal(checksum, expected[:4]) {
+		return nil, errors.New("invalid base58 checksum")
+	}
+	return data, nil
+}
+
+func doubleSha256(data []byte) [32]byte {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
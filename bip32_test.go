@@ -0,0 +1,43 @@
+package ergo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := parseDerivationPath("m/44'/429'/0'/0/7")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{44 + hardenedIndex, 429 + hardenedIndex, hardenedIndex, 0, 7}, path)
+	assert.Equal(t, "m/44'/429'/0'/0/7", formatDerivationPath(path))
+
+	// ergo-lib-c formats the path of a master key as m/
+	path, err = parseDerivationPath("m/")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+	path, err = parseDerivationPath("m")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	_, err = parseDerivationPath("44'/429'")
+	assert.Error(t, err)
+	_, err = parseDerivationPath("m/2147483648")
+	assert.Error(t, err)
+	_, err = parseDerivationPath("m//0")
+	assert.Error(t, err)
+}
+
+func TestBase58Check(t *testing.T) {
+	data := []byte{0, 0, 1, 2, 3, 255}
+	encoded := base58CheckEncode(data)
+	assert.Equal(t, "11", encoded[:2])
+
+	decoded, err := base58CheckDecode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	_, err = base58CheckDecode(encoded[:len(encoded)-1])
+	assert.Error(t, err)
+	_, err = base58CheckDecode("0OIl")
+	assert.Error(t, err)
+}
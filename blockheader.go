@@ -7,6 +7,7 @@ import "C"
 import (
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -16,6 +17,8 @@ type BlockHeader interface {
 	BlockId() BlockId
 	// Equals checks if provided BlockHeader is same
 	Equals(blockHeader BlockHeader) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.BlockHeaderPtr
 }
 
@@ -48,6 +51,10 @@ func NewBlockHeader(json string) (BlockHeader, error) {
 }
 
 func (b *blockHeader) BlockId() BlockId {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BlockIdPtr
 
 	C.ergo_lib_block_header_id(b.p, &p)
@@ -58,14 +65,30 @@ func (b *blockHeader) BlockId() BlockId {
 }
 
 func (b *blockHeader) Equals(blockHeader BlockHeader) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_block_header_eq(b.p, blockHeader.pointer())
 	return bool(res)
 }
 
 func (b *blockHeader) pointer() C.BlockHeaderPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *blockHeader) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBlockHeader(b)
+		b.p = nil
+	}
+}
+
 func finalizeBlockHeader(b *blockHeader) {
 	C.ergo_lib_block_header_delete(b.p)
 }
@@ -74,6 +97,8 @@ func finalizeBlockHeader(b *blockHeader) {
 type BlockId interface {
 	// Equals checks if provided BlockId is same
 	Equals(blockId BlockId) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.BlockIdPtr
 }
 
@@ -106,14 +131,30 @@ func NewBlockId(s string) (BlockId, error) {
 }
 
 func (b *blockId) Equals(blockId BlockId) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_block_id_eq(b.p, blockId.pointer())
 	return bool(res)
 }
 
 func (b *blockId) pointer() C.BlockIdPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *blockId) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBlockId(b)
+		b.p = nil
+	}
+}
+
 func finalizeBlockId(b *blockId) {
 	C.ergo_lib_block_id_delete(b.p)
 }
@@ -128,11 +169,15 @@ type BlockHeaders interface {
 	Add(blockHeader BlockHeader)
 	// All returns an iterator over all BlockHeader inside the collection
 	All() iter.Seq2[int, BlockHeader]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.BlockHeadersPtr
 }
 
 type blockHeaders struct {
-	p C.BlockHeadersPtr
+	p  C.BlockHeadersPtr
+	mu sync.RWMutex
 }
 
 func newBlockHeaders(b *blockHeaders) BlockHeaders {
@@ -150,11 +195,37 @@ func NewBlockHeaders() BlockHeaders {
 }
 
 func (b *blockHeaders) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return b.len()
+}
+
+func (b *blockHeaders) len() int {
+	if b.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_block_headers_len(b.p)
 	return int(res)
 }
 
 func (b *blockHeaders) Get(index int) (BlockHeader, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.get(index)
+}
+
+func (b *blockHeaders) get(index int) (BlockHeader, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.BlockHeaderPtr
 
 	res := C.ergo_lib_block_headers_get(b.p, C.uintptr_t(index), &p)
@@ -172,27 +243,54 @@ func (b *blockHeaders) Get(index int) (BlockHeader, error) {
 }
 
 func (b *blockHeaders) Add(blockHeader BlockHeader) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_block_headers_add(blockHeader.pointer(), b.p)
 }
 
 func (b *blockHeaders) All() iter.Seq2[int, BlockHeader] {
-	return func(yield func(int, BlockHeader) bool) {
-		for i := 0; i < b.Len(); i++ {
-			tk, err := b.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(b, b.len, b.get)
 }
 
 func (b *blockHeaders) pointer() C.BlockHeadersPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *blockHeaders) rlock() {
+	b.mu.RLock()
+}
+
+func (b *blockHeaders) runlock() {
+	b.mu.RUnlock()
+}
+
+func (b *blockHeaders) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBlockHeaders(b)
+		b.p = nil
+	}
+}
+
 func finalizeBlockHeaders(b *blockHeaders) {
 	C.ergo_lib_block_headers_delete(b.p)
 }
@@ -207,10 +305,13 @@ type BlockIds interface {
 	Add(blockId BlockId)
 	// All returns an iterator over all BlockId inside the collection
 	All() iter.Seq2[int, BlockId]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type blockIds struct {
-	p C.BlockIdsPtr
+	p  C.BlockIdsPtr
+	mu sync.RWMutex
 }
 
 func newBlockIds(b *blockIds) BlockIds {
@@ -229,11 +330,37 @@ func NewBlockIds() BlockIds {
 }
 
 func (b *blockIds) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return b.len()
+}
+
+func (b *blockIds) len() int {
+	if b.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_block_ids_len(b.p)
 	return int(res)
 }
 
 func (b *blockIds) Get(index int) (BlockId, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.get(index)
+}
+
+func (b *blockIds) get(index int) (BlockId, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.BlockIdPtr
 
 	res := C.ergo_lib_block_ids_get(b.p, C.uintptr_t(index), &p)
@@ -251,20 +378,43 @@ func (b *blockIds) Get(index int) (BlockId, error) {
 }
 
 func (b *blockIds) Add(blockId BlockId) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_block_ids_add(blockId.pointer(), b.p)
 }
 
 func (b *blockIds) All() iter.Seq2[int, BlockId] {
-	return func(yield func(int, BlockId) bool) {
-		for i := 0; i < b.Len(); i++ {
-			tk, err := b.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return readLockedAll(b, b.len, b.get)
+}
+
+func (b *blockIds) rlock() {
+	b.mu.RLock()
+}
+
+func (b *blockIds) runlock() {
+	b.mu.RUnlock()
+}
+
+func (b *blockIds) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBlockIds(b)
+		b.p = nil
 	}
 }
 
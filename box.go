@@ -5,11 +5,19 @@ package ergo
 */
 import "C"
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"iter"
 	"runtime"
+	"strconv"
+	"sync"
 	"unsafe"
 )
 
+// sBoxTypeCode is the type code of SBox in the sigma type serialization
+const sBoxTypeCode = 0x63
+
 type nonMandatoryRegisterId uint8
 
 const (
@@ -33,6 +41,14 @@ type BoxId interface {
 	Base16() string
 	// Equals checks if provided BoxId is same
 	Equals(boxId BoxId) bool
+	// MarshalJSON encodes the BoxId as JSON string in base16
+	MarshalJSON() ([]byte, error)
+	// MarshalText encodes the BoxId as base16
+	MarshalText() ([]byte, error)
+	// Value returns the BoxId as base16 string for storing it in a database
+	Value() (driver.Value, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.BoxIdPtr
 }
 
@@ -65,6 +81,10 @@ func NewBoxId(s string) (BoxId, error) {
 }
 
 func (b *boxId) Base16() string {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var boxIdStr *C.char
 
 	C.ergo_lib_box_id_to_str(b.p, &boxIdStr)
@@ -74,14 +94,54 @@ func (b *boxId) Base16() string {
 }
 
 func (b *boxId) Equals(boxId BoxId) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_box_id_eq(b.p, boxId.pointer())
 	return bool(res)
 }
 
+func (b *boxId) MarshalJSON() ([]byte, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	return json.Marshal(b.Base16())
+}
+
+func (b *boxId) MarshalText() ([]byte, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	return []byte(b.Base16()), nil
+}
+
+func (b *boxId) Value() (driver.Value, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	return b.Base16(), nil
+}
+
 func (b *boxId) pointer() C.BoxIdPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxId) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxId(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxId(b *boxId) {
 	C.ergo_lib_box_id_delete(b.p)
 }
@@ -92,6 +152,14 @@ type BoxValue interface {
 	Int64() int64
 	// Equals checks if provided BoxValue is same
 	Equals(boxValue BoxValue) bool
+	// MarshalJSON encodes the BoxValue as JSON number in nanoERGs
+	MarshalJSON() ([]byte, error)
+	// MarshalText encodes the BoxValue as decimal number of nanoERGs
+	MarshalText() ([]byte, error)
+	// Value returns the BoxValue as int64 in nanoERGs for storing it in a database
+	Value() (driver.Value, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.BoxValuePtr
 }
 
@@ -120,19 +188,63 @@ func NewBoxValue(value int64) (BoxValue, error) {
 }
 
 func (b *boxValue) Int64() int64 {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	value := C.ergo_lib_box_value_as_i64(b.p)
 	return int64(value)
 }
 
 func (b *boxValue) Equals(boxValue BoxValue) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_box_value_eq(b.p, boxValue.pointer())
 	return bool(res)
 }
 
+func (b *boxValue) MarshalJSON() ([]byte, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	return json.Marshal(b.Int64())
+}
+
+func (b *boxValue) MarshalText() ([]byte, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	return []byte(strconv.FormatInt(b.Int64(), 10)), nil
+}
+
+func (b *boxValue) Value() (driver.Value, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	return b.Int64(), nil
+}
+
 func (b *boxValue) pointer() C.BoxValuePtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxValue) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxValue(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxValue(b *boxValue) {
 	C.ergo_lib_box_value_delete(b.p)
 }
@@ -183,6 +295,10 @@ type BoxCandidate interface {
 	BoxValue() BoxValue
 	// Equals checks if provided BoxCandidate is same
 	Equals(candidate BoxCandidate) bool
+	// Bytes returns the BoxCandidate in sigma serialization, this equals the serialized Box without transaction id and index
+	Bytes() ([]byte, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ErgoBoxCandidatePtr
 }
 
@@ -196,6 +312,10 @@ func newBoxCandidate(b *boxCandidate) BoxCandidate {
 }
 
 func (b *boxCandidate) RegisterValue(registerId nonMandatoryRegisterId) (Constant, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ConstantPtr
 	rId := C.uchar(registerId)
 
@@ -213,11 +333,19 @@ func (b *boxCandidate) RegisterValue(registerId nonMandatoryRegisterId) (Constan
 }
 
 func (b *boxCandidate) CreationHeight() uint32 {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	height := C.ergo_lib_ergo_box_candidate_creation_height(b.p)
 	return uint32(height)
 }
 
 func (b *boxCandidate) Tokens() Tokens {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.TokensPtr
 
 	C.ergo_lib_ergo_box_candidate_tokens(b.p, &p)
@@ -228,6 +356,10 @@ func (b *boxCandidate) Tokens() Tokens {
 }
 
 func (b *boxCandidate) Tree() Tree {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoTreePtr
 
 	C.ergo_lib_ergo_box_candidate_ergo_tree(b.p, &p)
@@ -238,6 +370,10 @@ func (b *boxCandidate) Tree() Tree {
 }
 
 func (b *boxCandidate) BoxValue() BoxValue {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxValuePtr
 
 	C.ergo_lib_ergo_box_candidate_box_value(b.p, &p)
@@ -248,18 +384,98 @@ func (b *boxCandidate) BoxValue() BoxValue {
 }
 
 func (b *boxCandidate) Equals(candidate BoxCandidate) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_box_candidate_eq(b.p, candidate.pointer())
 	return bool(res)
 }
 
+func (b *boxCandidate) Bytes() ([]byte, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	fields, err := candidateFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendCandidate(nil, fields, nil), nil
+}
+
 func (b *boxCandidate) pointer() C.ErgoBoxCandidatePtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxCandidate) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxCandidate(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxCandidate(b *boxCandidate) {
 	C.ergo_lib_ergo_box_candidate_delete(b.p)
 }
 
+// NewBoxCandidateFromBytes parses a BoxCandidate from its sigma serialization as returned by BoxCandidate.Bytes
+func NewBoxCandidateFromBytes(bytes []byte) (BoxCandidate, error) {
+	// a candidate is a box without transaction id and index, parse it as box with an empty id and index 0
+	boxBytes := make([]byte, len(bytes), len(bytes)+33)
+	copy(boxBytes, bytes)
+	boxBytes = append(boxBytes, make([]byte, 33)...)
+
+	b, err := NewBoxFromBytes(boxBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	value := b.BoxValue()
+	defer value.Close()
+	ergoTree := b.Tree()
+	defer ergoTree.Close()
+	contract := NewContractFromTree(ergoTree)
+	defer contract.Close()
+
+	builder := NewBoxCandidateBuilder(value, contract, b.CreationHeight())
+	defer builder.Close()
+	// the serialized candidate may hold less than the current minimum box value, it is not validated here
+	builder.SetMinBoxValuePerByte(0)
+
+	boxTokens := b.Tokens()
+	defer boxTokens.Close()
+	for _, t := range boxTokens.All() {
+		id := t.Id()
+		amount := t.Amount()
+		builder.AddToken(id, amount)
+		id.Close()
+		amount.Close()
+		t.Close()
+	}
+
+	for registerId := R4; registerId <= R9; registerId++ {
+		c, err := b.RegisterValue(registerId)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			break
+		}
+		builder.SetRegisterValue(registerId, c)
+		c.Close()
+	}
+
+	return builder.Build()
+}
+
 // Box that is taking part in some transaction on the chain Differs with BoxCandidate
 // by added transaction id and an index in the input of that transaction
 type Box interface {
@@ -281,8 +497,14 @@ type Box interface {
 	JsonEIP12() (string, error)
 	// Size calculates serialized box size(in bytes)
 	Size() uint32
+	// Bytes returns the Box in sigma serialization. The blake2b256 hash of the bytes is the BoxId
+	Bytes() ([]byte, error)
+	// MarshalJSON encodes the Box as JSON in the format returned by Json
+	MarshalJSON() ([]byte, error)
 	// Equals checks if provided Box is same
 	Equals(box Box) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ErgoBoxPtr
 }
 
@@ -302,6 +524,8 @@ func newBox(b *box) Box {
 // txId - transaction id in which this box was "created" (participated in outputs)
 // index - index (in outputs) in the transaction
 func NewBox(boxValue BoxValue, creationHeight uint32, contract Contract, txId TxId, index uint16, tokens Tokens) (Box, error) {
+	defer readLock(tokens)()
+
 	var p C.ErgoBoxPtr
 
 	errPtr := C.ergo_lib_ergo_box_new(boxValue.pointer(), C.uint(creationHeight), contract.pointer(), txId.pointer(), C.ushort(index), tokens.pointer(), &p)
@@ -333,7 +557,43 @@ func NewBoxFromJson(json string) (Box, error) {
 	return newBox(b), nil
 }
 
+// NewBoxFromBytes parses a Box from its sigma serialization as returned by Box.Bytes
+func NewBoxFromBytes(bytes []byte) (Box, error) {
+	c, err := NewConstantFromBytes(append([]byte{sBoxTypeCode}, bytes...))
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var p C.ErgoBoxPtr
+
+	errPtr := C.ergo_lib_constant_to_ergo_box(c.pointer(), &p)
+	ergoErr := newError(errPtr)
+	if ergoErr.isError() {
+		return nil, ergoErr.error()
+	}
+
+	b := newBox(&box{p: p})
+
+	// the parser stops after the box, make sure nothing was left over
+	parsed, err := b.Bytes()
+	if err != nil {
+		b.Close()
+		return nil, err
+	}
+	if len(parsed) != len(bytes) {
+		b.Close()
+		return nil, errors.New("box bytes contain trailing data")
+	}
+
+	return b, nil
+}
+
 func (b *box) BoxId() BoxId {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxIdPtr
 
 	C.ergo_lib_ergo_box_id(b.p, &p)
@@ -344,6 +604,10 @@ func (b *box) BoxId() BoxId {
 }
 
 func (b *box) RegisterValue(registerId nonMandatoryRegisterId) (Constant, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ConstantPtr
 	rId := C.uchar(registerId)
 
@@ -361,11 +625,19 @@ func (b *box) RegisterValue(registerId nonMandatoryRegisterId) (Constant, error)
 }
 
 func (b *box) CreationHeight() uint32 {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	height := C.ergo_lib_ergo_box_creation_height(b.p)
 	return uint32(height)
 }
 
 func (b *box) Tokens() Tokens {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.TokensPtr
 	C.ergo_lib_ergo_box_tokens(b.p, &p)
 
@@ -375,6 +647,10 @@ func (b *box) Tokens() Tokens {
 }
 
 func (b *box) Tree() Tree {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoTreePtr
 	C.ergo_lib_ergo_box_ergo_tree(b.p, &p)
 
@@ -384,6 +660,10 @@ func (b *box) Tree() Tree {
 }
 
 func (b *box) BoxValue() BoxValue {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxValuePtr
 	C.ergo_lib_ergo_box_value(b.p, &p)
 
@@ -393,6 +673,10 @@ func (b *box) BoxValue() BoxValue {
 }
 
 func (b *box) Json() (string, error) {
+	if b.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_ergo_box_to_json(b.p, &outStr)
@@ -409,6 +693,10 @@ func (b *box) Json() (string, error) {
 }
 
 func (b *box) JsonEIP12() (string, error) {
+	if b.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_ergo_box_to_json_eip12(b.p, &outStr)
@@ -424,20 +712,66 @@ func (b *box) JsonEIP12() (string, error) {
 	return result, nil
 }
 
+func (b *box) MarshalJSON() ([]byte, error) {
+	s, err := b.Json()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
 func (b *box) Size() uint32 {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_box_bytes_size(b.p)
 	return uint32(res)
 }
 
+func (b *box) Bytes() ([]byte, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
+	// sigma-rust only exposes the box serialization as part of a constant of type SBox
+	c := NewConstantFromBox(b)
+	defer c.Close()
+
+	bytes, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes[1:], nil
+}
+
 func (b *box) Equals(box Box) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_box_eq(b.p, box.pointer())
 	return bool(res)
 }
 
 func (b *box) pointer() C.ErgoBoxPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *box) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBox(b)
+		b.p = nil
+	}
+}
+
 func finalizeBox(b *box) {
 	C.ergo_lib_ergo_box_delete(b.p)
 }
@@ -450,6 +784,8 @@ type BoxAssetsData interface {
 	Tokens() Tokens
 	// Equals checks if provided BoxAssetsData is same
 	Equals(boxAssetsData BoxAssetsData) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ErgoBoxAssetsDataPtr
 }
 
@@ -464,6 +800,8 @@ func newBoxAssetsData(b *boxAssetsData) BoxAssetsData {
 
 // NewBoxAssetsData creates a new BoxAssetsData from the supplied BoxValue and Tokens
 func NewBoxAssetsData(boxValue BoxValue, tokens Tokens) BoxAssetsData {
+	defer readLock(tokens)()
+
 	var p C.ErgoBoxAssetsDataPtr
 	C.ergo_lib_ergo_box_assets_data_new(boxValue.pointer(), tokens.pointer(), &p)
 
@@ -473,6 +811,10 @@ func NewBoxAssetsData(boxValue BoxValue, tokens Tokens) BoxAssetsData {
 }
 
 func (b *boxAssetsData) BoxValue() BoxValue {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxValuePtr
 	C.ergo_lib_ergo_box_assets_data_value(b.p, &p)
 
@@ -482,6 +824,10 @@ func (b *boxAssetsData) BoxValue() BoxValue {
 }
 
 func (b *boxAssetsData) Tokens() Tokens {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.TokensPtr
 	C.ergo_lib_ergo_box_assets_data_tokens(b.p, &p)
 
@@ -491,14 +837,30 @@ func (b *boxAssetsData) Tokens() Tokens {
 }
 
 func (b *boxAssetsData) Equals(boxAssetsData BoxAssetsData) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_box_assets_data_eq(b.p, boxAssetsData.pointer())
 	return bool(res)
 }
 
 func (b *boxAssetsData) pointer() C.ErgoBoxAssetsDataPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxAssetsData) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxAssetsData(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxAssetsData(b *boxAssetsData) {
 	C.ergo_lib_ergo_box_assets_data_delete(b.p)
 }
@@ -513,11 +875,15 @@ type BoxAssetsDataList interface {
 	Add(boxAssetsData BoxAssetsData)
 	// All returns an iterator over all BoxAssetsData inside the collection
 	All() iter.Seq2[int, BoxAssetsData]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ErgoBoxAssetsDataListPtr
 }
 
 type boxAssetsDataList struct {
-	p C.ErgoBoxAssetsDataListPtr
+	p  C.ErgoBoxAssetsDataListPtr
+	mu sync.RWMutex
 }
 
 func newBoxAssetsDataList(b *boxAssetsDataList) BoxAssetsDataList {
@@ -536,11 +902,37 @@ func NewBoxAssetsDataList() BoxAssetsDataList {
 }
 
 func (b *boxAssetsDataList) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return b.len()
+}
+
+func (b *boxAssetsDataList) len() int {
+	if b.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_ergo_box_assets_data_list_len(b.p)
 	return int(res)
 }
 
 func (b *boxAssetsDataList) Get(index int) (BoxAssetsData, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.get(index)
+}
+
+func (b *boxAssetsDataList) get(index int) (BoxAssetsData, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ErgoBoxAssetsDataPtr
 
 	res := C.ergo_lib_ergo_box_assets_data_list_get(b.p, C.uintptr_t(index), &p)
@@ -558,27 +950,54 @@ func (b *boxAssetsDataList) Get(index int) (BoxAssetsData, error) {
 }
 
 func (b *boxAssetsDataList) Add(boxAssetsData BoxAssetsData) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_assets_data_list_add(boxAssetsData.pointer(), b.p)
 }
 
 func (b *boxAssetsDataList) All() iter.Seq2[int, BoxAssetsData] {
-	return func(yield func(int, BoxAssetsData) bool) {
-		for i := 0; i < b.Len(); i++ {
-			tk, err := b.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(b, b.len, b.get)
 }
 
 func (b *boxAssetsDataList) pointer() C.ErgoBoxAssetsDataListPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxAssetsDataList) rlock() {
+	b.mu.RLock()
+}
+
+func (b *boxAssetsDataList) runlock() {
+	b.mu.RUnlock()
+}
+
+func (b *boxAssetsDataList) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxAssetsDataList(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxAssetsDataList(b *boxAssetsDataList) {
 	C.ergo_lib_ergo_box_assets_data_list_delete(b.p)
 }
@@ -593,11 +1012,15 @@ type BoxCandidates interface {
 	Add(boxCandidate BoxCandidate)
 	// All returns an iterator over all BoxCandidate inside the collection
 	All() iter.Seq2[int, BoxCandidate]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ErgoBoxCandidatesPtr
 }
 
 type boxCandidates struct {
-	p C.ErgoBoxCandidatesPtr
+	p  C.ErgoBoxCandidatesPtr
+	mu sync.RWMutex
 }
 
 func newBoxCandidates(b *boxCandidates) BoxCandidates {
@@ -616,11 +1039,37 @@ func NewBoxCandidates() BoxCandidates {
 }
 
 func (b *boxCandidates) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return b.len()
+}
+
+func (b *boxCandidates) len() int {
+	if b.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_ergo_box_candidates_len(b.p)
 	return int(res)
 }
 
 func (b *boxCandidates) Get(index int) (BoxCandidate, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.get(index)
+}
+
+func (b *boxCandidates) get(index int) (BoxCandidate, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ErgoBoxCandidatePtr
 
 	res := C.ergo_lib_ergo_box_candidates_get(b.p, C.uintptr_t(index), &p)
@@ -638,27 +1087,54 @@ func (b *boxCandidates) Get(index int) (BoxCandidate, error) {
 }
 
 func (b *boxCandidates) Add(boxCandidate BoxCandidate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_candidates_add(boxCandidate.pointer(), b.p)
 }
 
 func (b *boxCandidates) All() iter.Seq2[int, BoxCandidate] {
-	return func(yield func(int, BoxCandidate) bool) {
-		for i := 0; i < b.Len(); i++ {
-			tk, err := b.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(b, b.len, b.get)
 }
 
 func (b *boxCandidates) pointer() C.ErgoBoxCandidatesPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxCandidates) rlock() {
+	b.mu.RLock()
+}
+
+func (b *boxCandidates) runlock() {
+	b.mu.RUnlock()
+}
+
+func (b *boxCandidates) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxCandidates(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxCandidates(b *boxCandidates) {
 	C.ergo_lib_ergo_box_candidates_delete(b.p)
 }
@@ -673,11 +1149,15 @@ type Boxes interface {
 	Add(box Box)
 	// All returns an iterator over all Box inside the collection
 	All() iter.Seq2[int, Box]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ErgoBoxesPtr
 }
 
 type boxes struct {
-	p C.ErgoBoxesPtr
+	p  C.ErgoBoxesPtr
+	mu sync.RWMutex
 }
 
 func newBoxes(b *boxes) Boxes {
@@ -696,11 +1176,37 @@ func NewBoxes() Boxes {
 }
 
 func (b *boxes) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return b.len()
+}
+
+func (b *boxes) len() int {
+	if b.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_ergo_boxes_len(b.p)
 	return int(res)
 }
 
 func (b *boxes) Get(index int) (Box, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.get(index)
+}
+
+func (b *boxes) get(index int) (Box, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ErgoBoxPtr
 
 	res := C.ergo_lib_ergo_boxes_get(b.p, C.uintptr_t(index), &p)
@@ -718,27 +1224,54 @@ func (b *boxes) Get(index int) (Box, error) {
 }
 
 func (b *boxes) Add(box Box) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_boxes_add(box.pointer(), b.p)
 }
 
 func (b *boxes) All() iter.Seq2[int, Box] {
-	return func(yield func(int, Box) bool) {
-		for i := 0; i < b.Len(); i++ {
-			tk, err := b.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(b, b.len, b.get)
 }
 
 func (b *boxes) pointer() C.ErgoBoxesPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxes) rlock() {
+	b.mu.RLock()
+}
+
+func (b *boxes) runlock() {
+	b.mu.RUnlock()
+}
+
+func (b *boxes) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxes(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxes(b *boxes) {
 	C.ergo_lib_ergo_boxes_delete(b.p)
 }
@@ -2,6 +2,7 @@ package ergo
 
 import (
 	"github.com/stretchr/testify/assert"
+	"sync"
 	"testing"
 )
 
@@ -73,3 +74,134 @@ func TestNewBoxFromJson(t *testing.T) {
 	assert.Equal(t, testBoxValue, testErgoBox.BoxValue())
 	assert.Equal(t, testErgoTree, testErgoBox.Tree())
 }
+
+func TestBoxes_Close(t *testing.T) {
+	testBoxValue, _ := NewBoxValue(67500000000)
+	testTxId, _ := NewTxId("9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9")
+	testErgoTree, _ := NewTree("100204a00b08cd021dde34603426402615658f1d970cfa7c7bd92ac81a8b16eeebff264d59ce4604ea02d192a39a8cc7a70173007301")
+	testErgoBox, _ := NewBox(testBoxValue, 284761, NewContractFromTree(testErgoTree), testTxId, 1, NewTokens())
+
+	testBoxes := NewBoxes()
+	testBoxes.Add(testErgoBox)
+	testErgoBox.Close()
+
+	box, err := testBoxes.Get(0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(284761), box.CreationHeight())
+
+	testBoxes.Close()
+	_, err = testBoxes.Get(0)
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.Panics(t, func() { NewBoxes().Add(testErgoBox) })
+}
+
+func TestBoxes_All(t *testing.T) {
+	testBoxValue, _ := NewBoxValue(67500000000)
+	testTxId, _ := NewTxId("9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9")
+	testErgoTree, _ := NewTree("100204a00b08cd021dde34603426402615658f1d970cfa7c7bd92ac81a8b16eeebff264d59ce4604ea02d192a39a8cc7a70173007301")
+	testErgoBox, _ := NewBox(testBoxValue, 284761, NewContractFromTree(testErgoTree), testTxId, 1, NewTokens())
+
+	testBoxes := NewBoxes()
+	testBoxes.Add(testErgoBox)
+	// the loop body may modify the collection, boxes added while iterating are yielded too
+	var yielded int
+	for i := range testBoxes.All() {
+		if i < 2 {
+			testBoxes.Add(testErgoBox)
+		}
+		yielded++
+	}
+	assert.Equal(t, 3, yielded)
+
+	// closing the collection ends the iteration
+	yielded = 0
+	for range testBoxes.All() {
+		testBoxes.Close()
+		yielded++
+	}
+	assert.Equal(t, 1, yielded)
+	assert.PanicsWithValue(t, ErrClosed, func() { testBoxes.All() })
+}
+
+func TestBoxes_Concurrent(t *testing.T) {
+	testBoxValue, _ := NewBoxValue(67500000000)
+	testTxId, _ := NewTxId("9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9")
+	testErgoTree, _ := NewTree("100204a00b08cd021dde34603426402615658f1d970cfa7c7bd92ac81a8b16eeebff264d59ce4604ea02d192a39a8cc7a70173007301")
+	testContract := NewContractFromTree(testErgoTree)
+
+	testBoxes := NewBoxes()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(index uint16) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				testErgoBox, err := NewBox(testBoxValue, 284761, testContract, testTxId, index, NewTokens())
+				assert.NoError(t, err)
+				testBoxes.Add(testErgoBox)
+				for _, box := range testBoxes.All() {
+					assert.Equal(t, uint32(284761), box.CreationHeight())
+				}
+			}
+		}(uint16(i))
+	}
+	wg.Wait()
+
+	assert.Equal(t, 200, testBoxes.Len())
+}
+
+func TestBox_Bytes(t *testing.T) {
+	json := `{
+              "boxId": "e56847ed19b3dc6b72828fcfb992fdf7310828cf291221269b7ffc72fd66706e",
+              "value": 67500000000,
+              "ergoTree": "100204a00b08cd021dde34603426402615658f1d970cfa7c7bd92ac81a8b16eeebff264d59ce4604ea02d192a39a8cc7a70173007301",
+              "assets": [],
+              "creationHeight": 284761,
+              "additionalRegisters": {},
+              "transactionId": "9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9",
+              "index": 1
+            }`
+	testErgoBox, _ := NewBoxFromJson(json)
+
+	boxBytes, bytesErr := testErgoBox.Bytes()
+	assert.NoError(t, bytesErr)
+	assert.Equal(t, int(testErgoBox.Size()), len(boxBytes))
+
+	parsedBox, parseErr := NewBoxFromBytes(boxBytes)
+	assert.NoError(t, parseErr)
+	assert.True(t, testErgoBox.Equals(parsedBox))
+	assert.Equal(t, testErgoBox.BoxId(), parsedBox.BoxId())
+
+	_, trailingErr := NewBoxFromBytes(append(boxBytes, 0))
+	assert.Error(t, trailingErr)
+}
+
+func TestBoxCandidate_Bytes(t *testing.T) {
+	testBoxValue, _ := NewBoxValue(67500000000)
+	testTxId, _ := NewTxId("9148408c04c2e38a6402a7950d6157730fa7d49e9ab3b9cadec481d7769918e9")
+	testErgoTree, _ := NewTree("100204a00b08cd021dde34603426402615658f1d970cfa7c7bd92ac81a8b16eeebff264d59ce4604ea02d192a39a8cc7a70173007301")
+	testContract := NewContractFromTree(testErgoTree)
+	testTokenId, _ := NewTokenId("19475d9a78377ff0f36e9826cec439727bea522f6ffa3bda32e20d2f8b3103ac")
+	testTokenAmount, _ := NewTokenAmount(1)
+	testTokens := NewTokens()
+	testTokens.Add(NewToken(testTokenId, testTokenAmount))
+
+	builder := NewBoxCandidateBuilder(testBoxValue, testContract, 284761)
+	builder.AddToken(testTokenId, testTokenAmount)
+	builder.SetRegisterValue(R4, NewConstantFromInt32(1))
+	candidate, _ := builder.Build()
+
+	candidateBytes, bytesErr := candidate.Bytes()
+	assert.NoError(t, bytesErr)
+
+	parsedCandidate, parseErr := NewBoxCandidateFromBytes(candidateBytes)
+	assert.NoError(t, parseErr)
+	assert.True(t, candidate.Equals(parsedCandidate))
+
+	// a box serializes as its candidate followed by transaction id and index
+	testErgoBox, _ := NewBox(testBoxValue, 284761, testContract, testTxId, 0, testTokens)
+	boxBytes, _ := testErgoBox.Bytes()
+	noRegisters, _ := NewBoxCandidateFromBytes(boxBytes[:len(boxBytes)-33])
+	assert.Equal(t, testErgoBox.BoxValue(), noRegisters.BoxValue())
+	assert.Equal(t, 1, noRegisters.Tokens().Len())
+}
@@ -6,6 +6,7 @@ package ergo
 import "C"
 import (
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -40,10 +41,13 @@ type BoxCandidateBuilder interface {
 	AddToken(tokenId TokenId, tokenAmount TokenAmount)
 	// Build builds the box candidate
 	Build() (BoxCandidate, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type boxCandidateBuilder struct {
-	p C.ErgoBoxCandidateBuilderPtr
+	p  C.ErgoBoxCandidateBuilderPtr
+	mu sync.RWMutex
 }
 
 func newBoxCandidateBuilder(b *boxCandidateBuilder) BoxCandidateBuilder {
@@ -68,19 +72,47 @@ func NewBoxCandidateBuilder(boxValue BoxValue, contract Contract, creationHeight
 }
 
 func (b *boxCandidateBuilder) SetMinBoxValuePerByte(minBoxValuePerByte uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_candidate_builder_set_min_box_value_per_byte(b.p, C.uint(minBoxValuePerByte))
 }
 
 func (b *boxCandidateBuilder) MinBoxValuePerByte() uint32 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_box_candidate_builder_min_box_value_per_byte(b.p)
 	return uint32(res)
 }
 
 func (b *boxCandidateBuilder) SetValue(boxValue BoxValue) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_candidate_builder_set_value(b.p, boxValue.pointer())
 }
 
 func (b *boxCandidateBuilder) Value() BoxValue {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxValuePtr
 	C.ergo_lib_ergo_box_candidate_builder_value(b.p, &p)
 	bv := &boxValue{p: p}
@@ -88,6 +120,13 @@ func (b *boxCandidateBuilder) Value() BoxValue {
 }
 
 func (b *boxCandidateBuilder) CalcBoxSizeBytes() (uint32, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		return 0, ErrClosed
+	}
+
 	res := C.ergo_lib_ergo_box_candidate_builder_calc_box_size_bytes(b.p)
 	err := newError(res.error)
 	if err.isError() {
@@ -97,6 +136,13 @@ func (b *boxCandidateBuilder) CalcBoxSizeBytes() (uint32, error) {
 }
 
 func (b *boxCandidateBuilder) CalcMinBoxValue() (BoxValue, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.BoxValuePtr
 	errPtr := C.ergo_lib_ergo_box_candidate_calc_min_box_value(b.p, &p)
 	err := newError(errPtr)
@@ -108,10 +154,24 @@ func (b *boxCandidateBuilder) CalcMinBoxValue() (BoxValue, error) {
 }
 
 func (b *boxCandidateBuilder) SetRegisterValue(registerId nonMandatoryRegisterId, constant Constant) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_candidate_builder_set_register_value(b.p, C.uchar(registerId), constant.pointer())
 }
 
 func (b *boxCandidateBuilder) RegisterValue(registerId nonMandatoryRegisterId) (Constant, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ConstantPtr
 	res := C.ergo_lib_ergo_box_candidate_builder_register_value(b.p, C.uchar(registerId), &p)
 	err := newError(res.error)
@@ -127,10 +187,24 @@ func (b *boxCandidateBuilder) RegisterValue(registerId nonMandatoryRegisterId) (
 }
 
 func (b *boxCandidateBuilder) DeleteRegisterValue(registerId nonMandatoryRegisterId) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_candidate_builder_delete_register_value(b.p, C.uchar(registerId))
 }
 
 func (b *boxCandidateBuilder) MintToken(token Token, tokenName string, tokenDesc string, numDecimals uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	tknNameStr := C.CString(tokenName)
 	defer C.free(unsafe.Pointer(tknNameStr))
 
@@ -141,10 +215,24 @@ func (b *boxCandidateBuilder) MintToken(token Token, tokenName string, tokenDesc
 }
 
 func (b *boxCandidateBuilder) AddToken(tokenId TokenId, tokenAmount TokenAmount) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_ergo_box_candidate_builder_add_token(b.p, tokenId.pointer(), tokenAmount.pointer())
 }
 
 func (b *boxCandidateBuilder) Build() (BoxCandidate, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ErgoBoxCandidatePtr
 
 	errPtr := C.ergo_lib_ergo_box_candidate_builder_build(b.p, &p)
@@ -157,6 +245,17 @@ func (b *boxCandidateBuilder) Build() (BoxCandidate, error) {
 	return newBoxCandidate(bc), nil
 }
 
+func (b *boxCandidateBuilder) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxCandidateBuilder(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxCandidateBuilder(b *boxCandidateBuilder) {
 	C.ergo_lib_ergo_box_candidate_builder_delete(b.p)
 }
@@ -14,6 +14,8 @@ type BoxSelection interface {
 	ChangeBoxes() BoxAssetsDataList
 	// Equals checks if provided BoxSelection is same
 	Equals(boxSelection BoxSelection) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.BoxSelectionPtr
 }
 
@@ -28,6 +30,8 @@ func newBoxSelection(b *boxSelection) BoxSelection {
 
 // NewBoxSelection creates a selection to easily inject custom selection algorithms
 func NewBoxSelection(ergoBoxes Boxes, changeErgoBoxes BoxAssetsDataList) BoxSelection {
+	defer readLock(ergoBoxes, changeErgoBoxes)()
+
 	var p C.BoxSelectionPtr
 	C.ergo_lib_box_selection_new(ergoBoxes.pointer(), changeErgoBoxes.pointer(), &p)
 	bs := &boxSelection{p: p}
@@ -35,6 +39,10 @@ func NewBoxSelection(ergoBoxes Boxes, changeErgoBoxes BoxAssetsDataList) BoxSele
 }
 
 func (b *boxSelection) Boxes() Boxes {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoBoxesPtr
 	C.ergo_lib_box_selection_boxes(b.p, &p)
 	bo := &boxes{p: p}
@@ -42,6 +50,10 @@ func (b *boxSelection) Boxes() Boxes {
 }
 
 func (b *boxSelection) ChangeBoxes() BoxAssetsDataList {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoBoxAssetsDataListPtr
 	C.ergo_lib_box_selection_change(b.p, &p)
 	ba := &boxAssetsDataList{p: p}
@@ -49,14 +61,30 @@ func (b *boxSelection) ChangeBoxes() BoxAssetsDataList {
 }
 
 func (b *boxSelection) Equals(boxSelection BoxSelection) bool {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_box_selection_eq(b.p, boxSelection.pointer())
 	return bool(res)
 }
 
 func (b *boxSelection) pointer() C.BoxSelectionPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *boxSelection) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeBoxSelection(b)
+		b.p = nil
+	}
+}
+
 func finalizeBoxSelection(b *boxSelection) {
 	C.ergo_lib_box_selection_delete(b.p)
 }
@@ -70,6 +98,8 @@ type SimpleBoxSelector interface {
 	// targetTokens - amount of tokens needed
 	// Returns: selected inputs and box assets(value+tokens) with change
 	Select(inputs Boxes, targetBalance BoxValue, targetTokens Tokens) (BoxSelection, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type simpleBoxSelector struct {
@@ -90,6 +120,12 @@ func NewSimpleBoxSelector() SimpleBoxSelector {
 }
 
 func (b *simpleBoxSelector) Select(inputs Boxes, targetBalance BoxValue, targetTokens Tokens) (BoxSelection, error) {
+	defer readLock(inputs, targetTokens)()
+
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.BoxSelectionPtr
 	errPtr := C.ergo_lib_simple_box_selector_select(b.p, inputs.pointer(), targetBalance.pointer(), targetTokens.pointer(), &p)
 	err := newError(errPtr)
@@ -102,6 +138,14 @@ func (b *simpleBoxSelector) Select(inputs Boxes, targetBalance BoxValue, targetT
 	return newBoxSelection(bs), nil
 }
 
+func (s *simpleBoxSelector) Close() {
+	if s.p != nil {
+		runtime.SetFinalizer(s, nil)
+		finalizeSimpleBoxSelector(s)
+		s.p = nil
+	}
+}
+
 func finalizeSimpleBoxSelector(s *simpleBoxSelector) {
 	C.ergo_lib_simple_box_selector_delete(s.p)
 }
@@ -7,10 +7,13 @@ import "C"
 import (
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
 type ByteArray interface {
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ByteArrayPtr
 }
 
@@ -40,9 +43,21 @@ func NewByteArray(bytes []byte) (ByteArray, error) {
 }
 
 func (b *byteArray) pointer() C.ByteArrayPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *byteArray) Close() {
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeByteArray(b)
+		b.p = nil
+	}
+}
+
 func finalizeByteArray(b *byteArray) {
 	C.ergo_lib_byte_array_delete(b.p)
 }
@@ -52,11 +67,15 @@ type ByteArrays interface {
 	Get(index int) (ByteArray, error)
 	Add(byteArray ByteArray)
 	All() iter.Seq2[int, ByteArray]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ByteArraysPtr
 }
 
 type byteArrays struct {
-	p C.ByteArraysPtr
+	p  C.ByteArraysPtr
+	mu sync.RWMutex
 }
 
 func newByteArrays(b *byteArrays) ByteArrays {
@@ -72,11 +91,37 @@ func NewByteArrays() ByteArrays {
 }
 
 func (b *byteArrays) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
+	return b.len()
+}
+
+func (b *byteArrays) len() int {
+	if b.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_byte_arrays_len(b.p)
 	return int(res)
 }
 
 func (b *byteArrays) Get(index int) (ByteArray, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.get(index)
+}
+
+func (b *byteArrays) get(index int) (ByteArray, error) {
+	if b.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ByteArrayPtr
 
 	res := C.ergo_lib_byte_arrays_get(b.p, C.uintptr_t(index), &p)
@@ -94,27 +139,54 @@ func (b *byteArrays) Get(index int) (ByteArray, error) {
 }
 
 func (b *byteArrays) Add(byteArray ByteArray) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_byte_arrays_add(byteArray.pointer(), b.p)
 }
 
 func (b *byteArrays) All() iter.Seq2[int, ByteArray] {
-	return func(yield func(int, ByteArray) bool) {
-		for i := 0; i < b.Len(); i++ {
-			tk, err := b.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(b, b.len, b.get)
 }
 
 func (b *byteArrays) pointer() C.ByteArraysPtr {
+	if b.p == nil {
+		panic(ErrClosed)
+	}
+
 	return b.p
 }
 
+func (b *byteArrays) rlock() {
+	b.mu.RLock()
+}
+
+func (b *byteArrays) runlock() {
+	b.mu.RUnlock()
+}
+
+func (b *byteArrays) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.p != nil {
+		runtime.SetFinalizer(b, nil)
+		finalizeByteArrays(b)
+		b.p = nil
+	}
+}
+
 func finalizeByteArrays(b *byteArrays) {
 	C.ergo_lib_byte_arrays_delete(b.p)
 }
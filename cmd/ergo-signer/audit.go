@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// decisions of an auditEntry
+const (
+	decisionSigned   = "signed"
+	decisionRejected = "rejected"
+	decisionFailed   = "failed"
+)
+
+// auditEntry is a line of the audit log. Every entry contains the hash of the line before,
+// so removing or changing entries breaks the chain
+type auditEntry struct {
+	Time     time.Time `json:"time"`
+	TxId     string    `json:"txId,omitempty"`
+	Decision string    `json:"decision"`
+	Reason   string    `json:"reason,omitempty"`
+	Outflow  *outflow  `json:"outflow,omitempty"`
+	Remote   string    `json:"remote,omitempty"`
+	PrevHash string    `json:"prevHash"`
+}
+
+// auditLog appends entries to a file, one JSON object per line. The hash chain cannot reveal entries cut off
+// at the end, so the number of entries and the hash of the last one are also kept in a head file
+type auditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	headPath string
+	entries  int
+	lastHash string
+}
+
+// auditHead is the content of the head file of an auditLog
+type auditHead struct {
+	Entries int    `json:"entries"`
+	Hash    string `json:"hash"`
+}
+
+// openAuditLog opens the audit log at path with its head file at headPath and returns the entries it already
+// contains. It fails if the hash chain of the entries is broken or the log does not reach the head
+func openAuditLog(path string, headPath string) (*auditLog, []auditEntry, error) {
+	head, headExists, err := readAuditHead(headPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []auditEntry
+	lastHash := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var e auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("audit log %s line %d: %w", path, line, err)
+		}
+		if e.PrevHash != lastHash {
+			file.Close()
+			return nil, nil, fmt.Errorf("audit log %s line %d: hash chain is broken", path, line)
+		}
+		lastHash = lineHash(scanner.Bytes())
+		entries = append(entries, e)
+		if line == head.Entries && lastHash != head.Hash {
+			file.Close()
+			return nil, nil, fmt.Errorf("audit log %s line %d: hash differs from the head %s", path, line, headPath)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if !headExists && len(entries) > 0 {
+		file.Close()
+		return nil, nil, fmt.Errorf("audit log %s has entries but its head %s is missing", path, headPath)
+	}
+	// the log may be ahead of the head if the signer stopped between writing both
+	if len(entries) < head.Entries {
+		file.Close()
+		return nil, nil, fmt.Errorf("audit log %s is truncated, it has %d entries but the head %s has %d", path, len(entries), headPath, head.Entries)
+	}
+
+	return &auditLog{file: file, headPath: headPath, entries: len(entries), lastHash: lastHash}, entries, nil
+}
+
+// readAuditHead reads the head file at path and reports whether it exists
+func readAuditHead(path string) (auditHead, bool, error) {
+	var head auditHead
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return head, false, nil
+	}
+	if err != nil {
+		return head, false, err
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return head, false, fmt.Errorf("audit head %s: %w", path, err)
+	}
+	return head, true, nil
+}
+
+// writeHead replaces the head file, it returns once the new head is durable
+func (a *auditLog) writeHead() error {
+	data, err := json.Marshal(auditHead{Entries: a.entries, Hash: a.lastHash})
+	if err != nil {
+		return err
+	}
+	tmpPath := a.headPath + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, a.headPath)
+}
+
+// append writes e to the log, it returns once the entry is durable
+func (a *auditLog) append(e auditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e.PrevHash = a.lastHash
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := a.file.Sync(); err != nil {
+		return err
+	}
+	a.entries++
+	a.lastHash = lineHash(line)
+	return a.writeHead()
+}
+
+func (a *auditLog) Close() error {
+	return a.file.Close()
+}
+
+func lineHash(line []byte) string {
+	hash := sha256.Sum256(line)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	headPath := path + ".head"
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	audit, entries, err := openAuditLog(path, headPath)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.NoError(t, audit.append(auditEntry{Time: now, TxId: "01", Decision: decisionSigned, Outflow: &outflow{Erg: 10}}))
+	assert.NoError(t, audit.append(auditEntry{Time: now, TxId: "02", Decision: decisionRejected, Reason: "destination is not allowed"}))
+	assert.NoError(t, audit.Close())
+
+	// reopening continues the chain
+	audit, entries, err = openAuditLog(path, headPath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "", entries[0].PrevHash)
+	assert.Equal(t, &outflow{Erg: 10}, entries[0].Outflow)
+	assert.Equal(t, decisionRejected, entries[1].Decision)
+	assert.NoError(t, audit.append(auditEntry{Time: now, Decision: decisionFailed, Reason: "invalid request"}))
+	assert.NoError(t, audit.Close())
+
+	_, entries, err = openAuditLog(path, headPath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestAuditLog_BrokenChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	headPath := path + ".head"
+	audit, _, err := openAuditLog(path, headPath)
+	assert.NoError(t, err)
+	assert.NoError(t, audit.append(auditEntry{TxId: "01", Decision: decisionSigned, Outflow: &outflow{Erg: 10}}))
+	assert.NoError(t, audit.append(auditEntry{TxId: "02", Decision: decisionSigned, Outflow: &outflow{Erg: 20}}))
+	assert.NoError(t, audit.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), `"erg":10`, `"erg":1`, 1)), 0600))
+
+	_, _, err = openAuditLog(path, headPath)
+	assert.ErrorContains(t, err, "line 2: hash chain is broken")
+}
+
+func TestAuditLog_Head(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	headPath := filepath.Join(t.TempDir(), "audit.head")
+	audit, _, err := openAuditLog(path, headPath)
+	assert.NoError(t, err)
+	assert.NoError(t, audit.append(auditEntry{TxId: "01", Decision: decisionSigned, Outflow: &outflow{Erg: 10}}))
+	head, err := os.ReadFile(headPath)
+	assert.NoError(t, err)
+	assert.NoError(t, audit.append(auditEntry{TxId: "02", Decision: decisionSigned, Outflow: &outflow{Erg: 20}}))
+	assert.NoError(t, audit.Close())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.SplitAfter(string(data), "\n")
+
+	// the last entry is cut off
+	assert.NoError(t, os.WriteFile(path, []byte(lines[0]), 0600))
+	_, _, err = openAuditLog(path, headPath)
+	assert.ErrorContains(t, err, "is truncated, it has 1 entries but the head")
+
+	// the last entry is replaced by another one continuing the chain
+	assert.NoError(t, os.WriteFile(path, []byte(lines[0]+strings.Replace(lines[1], `"erg":20`, `"erg":2`, 1)), 0600))
+	_, _, err = openAuditLog(path, headPath)
+	assert.ErrorContains(t, err, "line 2: hash differs from the head")
+
+	// the log may be ahead of the head if the signer stopped before writing the head
+	assert.NoError(t, os.WriteFile(path, data, 0600))
+	assert.NoError(t, os.WriteFile(headPath, head, 0600))
+	audit, entries, err := openAuditLog(path, headPath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.NoError(t, audit.Close())
+
+	// the head is missing
+	assert.NoError(t, os.Remove(headPath))
+	_, _, err = openAuditLog(path, headPath)
+	assert.ErrorContains(t, err, "has entries but its head")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	ergo "github.com/sigmaspace-io/ergo-lib-go"
+)
+
+// secretKeyLength is the length of a secret key in a Keystore holding secret keys
+const secretKeyLength = 32
+
+// keyring holds the keys of the signer, unlocked from a Keystore
+type keyring struct {
+	// account derives the EIP-3 addresses of a Keystore holding a seed
+	account *ergo.Account
+	// secrets are the keys of a Keystore holding secret keys
+	secrets ergo.SecretKeys
+	// ownTrees are the trees of the addresses of the signer, outputs to them are change
+	ownTrees map[string]bool
+}
+
+// unlockKeyring unlocks keystore with password. For a seed the first addresses of account are derived
+func unlockKeyring(keystore *ergo.Keystore, password string, account uint32, addresses uint32) (*keyring, error) {
+	k := &keyring{ownTrees: make(map[string]bool)}
+
+	master, err := keystore.UnlockExtendedSecretKey(password)
+	if err == nil {
+		k.account = ergo.NewAccount(master, account)
+		for i := uint32(0); i < addresses; i++ {
+			address, err := k.account.Address(i)
+			if err != nil {
+				return nil, err
+			}
+			err = k.addOwnTree(address)
+			address.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return k, nil
+	}
+	// only a Keystore of secret keys is read as such, any other error is returned
+	if !errors.Is(err, ergo.ErrKeystoreSecretKeys) {
+		return nil, err
+	}
+
+	plaintext, err := keystore.Export(password)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+	k.secrets = ergo.NewSecretKeys()
+	for i := 0; i+secretKeyLength <= len(plaintext); i += secretKeyLength {
+		secret, err := ergo.NewSecretKeyFromBytes(plaintext[i : i+secretKeyLength])
+		if err != nil {
+			k.secrets.Close()
+			return nil, err
+		}
+		address := secret.Address()
+		err = k.addOwnTree(address)
+		address.Close()
+		k.secrets.Add(secret)
+		secret.Close()
+		if err != nil {
+			k.secrets.Close()
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+func (k *keyring) addOwnTree(address ergo.Address) error {
+	tree := address.Tree()
+	defer tree.Close()
+	treeStr, err := tree.Base16()
+	if err != nil {
+		return err
+	}
+	k.ownTrees[treeStr] = true
+	return nil
+}
+
+// wallet returns a Wallet to sign a transaction spending boxes, the caller closes it
+func (k *keyring) wallet(boxes ergo.Boxes) (ergo.Wallet, error) {
+	if k.account != nil {
+		wallet, err := k.account.Wallet(boxes)
+		if err != nil {
+			return nil, fmt.Errorf("not a box of the signer: %w", err)
+		}
+		return wallet, nil
+	}
+	return ergo.NewWalletFromSecretKeys(k.secrets), nil
+}
@@ -0,0 +1,216 @@
+// Command ergo-signer is a signing daemon. It serves the protocol of ergo.RemoteSigner and signs a transaction
+// only if it passes a policy: the outputs go to allowed addresses or contracts and the outflow stays within
+// limits per transaction and per day. Every decision is appended to an audit log.
+//
+// The unsigned transaction is reduced with the last block headers of an Ergo node and signed with the keys
+// of a Keystore, which is unlocked with the password in the environment variable ERGO_SIGNER_PASSWORD.
+//
+// Clients authenticate with the bearer token in the environment variable ERGO_SIGNER_TOKEN, which they send in
+// the Authorization header, e.g. through the Client of ergo.RemoteSigner. The token is required unless the
+// signer listens on a unix socket ("listen": "unix:/run/ergo-signer/signer.sock"), which is only accessible
+// to the user running the signer. Create the socket in a directory other users cannot access, as the
+// permissions of the socket are only restricted right after it is created.
+//
+// Only unsigned transactions are accepted. ergo-lib-c can neither serialize nor parse a ReducedTransaction
+// (see ergo.ReducedTransaction), so a reduced transaction cannot be sent to the signer. The signer reduces
+// the transaction itself, which also keeps the block headers it is signed against under its control.
+//
+// Every limit has to be set explicitly. maxErgPerTx and maxErgPerDay are required and a token missing from
+// maxTokensPerTx or maxTokensPerDay cannot be sent. A limit of -1 disables it, which is logged at startup.
+//
+// Usage:
+//
+//	ergo-signer -config signer.json
+//
+// with a configuration like
+//
+//	{
+//	  "listen": "127.0.0.1:9060",
+//	  "node": "http://127.0.0.1:9053",
+//	  "keystore": "keystore.json",
+//	  "account": 0,
+//	  "addresses": 20,
+//	  "auditLog": "audit.log",
+//	  "auditHead": "/var/lib/ergo-signer/audit.log.head",
+//	  "policy": {
+//	    "allowedAddresses": ["9hRTUYF37..."],
+//	    "allowedTemplateHashes": [],
+//	    "maxErgPerTx": 10000000000,
+//	    "maxErgPerDay": 50000000000,
+//	    "maxTokensPerTx": {"03faf2cb329f2e90d6d23b58d91bbb6c046aa143261cc21f52fbe2824bfcbf04": 1000},
+//	    "maxTokensPerDay": {"03faf2cb329f2e90d6d23b58d91bbb6c046aa143261cc21f52fbe2824bfcbf04": -1}
+//	  }
+//	}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	ergo "github.com/sigmaspace-io/ergo-lib-go"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// passwordEnv is the environment variable holding the password of the Keystore
+const passwordEnv = "ERGO_SIGNER_PASSWORD"
+
+// tokenEnv is the environment variable holding the bearer token clients have to send
+const tokenEnv = "ERGO_SIGNER_TOKEN"
+
+// unixPrefix marks a Listen address as the path of a unix socket
+const unixPrefix = "unix:"
+
+// Config is the configuration file of ergo-signer
+type Config struct {
+	// Listen is the TCP address the signer listens on or the path of a unix socket prefixed with unix:
+	Listen string `json:"listen"`
+	// Node is the URL of the REST API of the Ergo node providing the block headers
+	Node string `json:"node"`
+	// Keystore is the path of the Keystore file
+	Keystore string `json:"keystore"`
+	// Account is the EIP-3 account of a Keystore holding a seed
+	Account uint32 `json:"account"`
+	// Addresses is the number of addresses of the account the signer signs for
+	Addresses uint32 `json:"addresses"`
+	// AuditLog is the path of the audit log
+	AuditLog string `json:"auditLog"`
+	// AuditHead is the path of the file holding the number of entries and the last hash of the audit log, by
+	// default the path of the audit log followed by .head. Keep it apart from the audit log, e.g. on another
+	// volume, as removing the end of the log together with the head would go unnoticed
+	AuditHead string `json:"auditHead"`
+	// Policy are the rules for signing
+	Policy PolicyConfig `json:"policy"`
+}
+
+func main() {
+	configPath := flag.String("config", "signer.json", "path of the configuration file")
+	flag.Parse()
+
+	if err := run(*configPath); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(configPath string) error {
+	config, err := readConfig(configPath)
+	if err != nil {
+		return err
+	}
+	p, err := newPolicy(config.Policy)
+	if err != nil {
+		return err
+	}
+	for _, name := range p.disabledLimits() {
+		log.Printf("WARNING: policy limit %s is disabled, the outflow is not restricted", name)
+	}
+	token := os.Getenv(tokenEnv)
+	os.Unsetenv(tokenEnv)
+	if token == "" && !strings.HasPrefix(config.Listen, unixPrefix) {
+		return fmt.Errorf("%s is required unless the signer listens on a unix socket", tokenEnv)
+	}
+
+	keystore, err := ergo.ReadKeystoreFile(config.Keystore)
+	if err != nil {
+		return err
+	}
+	keys, err := unlockKeyring(keystore, os.Getenv(passwordEnv), config.Account, config.Addresses)
+	if err != nil {
+		return fmt.Errorf("unlock keystore: %w", err)
+	}
+	os.Unsetenv(passwordEnv)
+
+	audit, entries, err := openAuditLog(config.AuditLog, config.AuditHead)
+	if err != nil {
+		return err
+	}
+	defer audit.Close()
+
+	s := &server{
+		keys:   keys,
+		policy: p,
+		ledger: replayLedger(entries, time.Now()),
+		audit:  audit,
+		node:   &node{url: config.Node, client: &http.Client{Timeout: 30 * time.Second}},
+		now:    time.Now,
+		token:  token,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/sign", s)
+	httpServer := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	listener, err := listen(config.Listen)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("ergo-signer listening on %s", config.Listen)
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// listen listens on the TCP address or, if address starts with unix:, on a unix socket only the user running
+// the signer can connect to
+func listen(address string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(address, unixPrefix)
+	if !ok {
+		return net.Listen("tcp", address)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// replayLedger returns the ledger of the transactions signed in the 24 hours before now according to the
+// entries of the audit log, so the daily limits account for the transactions signed before a restart
+func replayLedger(entries []auditEntry, now time.Time) *ledger {
+	l := &ledger{}
+	for _, e := range entries {
+		if e.Decision == decisionSigned && e.Outflow != nil && now.Sub(e.Time) < ledgerWindow {
+			l.add(e.Time, *e.Outflow)
+		}
+	}
+	return l
+}
+
+func readConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{Listen: "127.0.0.1:9060", Addresses: ergo.DefaultGapLimit}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if config.Node == "" || config.Keystore == "" || config.AuditLog == "" {
+		return nil, fmt.Errorf("config %s: node, keystore and auditLog are required", path)
+	}
+	if config.AuditHead == "" {
+		config.AuditHead = config.AuditLog + ".head"
+	}
+	return config, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	ergo "github.com/sigmaspace-io/ergo-lib-go"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// stateContextHeaders is the number of headers of a StateContext
+const stateContextHeaders = 10
+
+// node fetches the StateContext to sign with from the REST API of an Ergo node
+type node struct {
+	url    string
+	client *http.Client
+}
+
+func (n *node) stateContext(ctx context.Context) (ergo.StateContext, error) {
+	url := fmt.Sprintf("%s/blocks/lastHeaders/%d", strings.TrimSuffix(n.url, "/"), stateContextHeaders)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := n.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("node %s: %s", url, response.Status)
+	}
+
+	var headersJson []json.RawMessage
+	if err := json.NewDecoder(response.Body).Decode(&headersJson); err != nil {
+		return nil, fmt.Errorf("node %s: %w", url, err)
+	}
+	// the StateContext expects the last header first
+	heights := make([]uint32, len(headersJson))
+	for i, h := range headersJson {
+		var header struct {
+			Height uint32 `json:"height"`
+		}
+		if err := json.Unmarshal(h, &header); err != nil {
+			return nil, fmt.Errorf("node %s: %w", url, err)
+		}
+		heights[i] = header.Height
+	}
+	order := make([]int, len(headersJson))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(heights[b], heights[a]) })
+
+	headers := ergo.NewBlockHeaders()
+	defer headers.Close()
+	for _, i := range order {
+		header, err := ergo.NewBlockHeader(string(headersJson[i]))
+		if err != nil {
+			return nil, err
+		}
+		headers.Add(header)
+		header.Close()
+	}
+	if headers.Len() == 0 {
+		return nil, fmt.Errorf("node %s: no headers", url)
+	}
+
+	last, err := headers.Get(0)
+	if err != nil {
+		return nil, err
+	}
+	defer last.Close()
+	preHeader := ergo.NewPreHeader(last)
+	defer preHeader.Close()
+	parameters := ergo.DefaultParameters()
+	defer parameters.Close()
+	return ergo.NewStateContext(preHeader, headers, parameters)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	ergo "github.com/sigmaspace-io/ergo-lib-go"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// minerFeeTree is the tree of the miner fee contract, paying the fee is always allowed
+const minerFeeTree = "1005040004000e36100204a00b08cd0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798ea02d192a39a8cc7a701730073011001020402d19683030193a38cc7b2a57300000193c2b2a57301007473027303830108cdeeac93b1a57304"
+
+// Unlimited disables a limit of PolicyConfig. Limits have to be set explicitly, a limit missing from the
+// configuration is an error for ERG and allows no outflow for a token
+const Unlimited = -1
+
+// PolicyConfig configures the rules a transaction has to pass to be signed
+type PolicyConfig struct {
+	// AllowedAddresses are the addresses outputs may be sent to besides the addresses of the signer
+	AllowedAddresses []string `json:"allowedAddresses"`
+	// AllowedTemplateHashes are the template hashes of contracts outputs may be sent to, see Tree.TemplateHash
+	AllowedTemplateHashes []string `json:"allowedTemplateHashes"`
+	// MaxErgPerTx is the maximum outflow of nanoERG in a transaction, including the fee
+	MaxErgPerTx int64 `json:"maxErgPerTx"`
+	// MaxErgPerDay is the maximum outflow of nanoERG of the transactions signed in the last 24 hours
+	MaxErgPerDay int64 `json:"maxErgPerDay"`
+	// MaxTokensPerTx is the maximum outflow of a transaction by token id, tokens not listed cannot be sent
+	MaxTokensPerTx map[string]int64 `json:"maxTokensPerTx"`
+	// MaxTokensPerDay is the maximum outflow of the transactions signed in the last 24 hours by token id,
+	// tokens not listed cannot be sent
+	MaxTokensPerDay map[string]int64 `json:"maxTokensPerDay"`
+}
+
+// outflow is the value a transaction moves away from the addresses of the signer, sent or burned
+type outflow struct {
+	Erg    int64            `json:"erg"`
+	Tokens map[string]int64 `json:"tokens,omitempty"`
+}
+
+func (o *outflow) add(other outflow) {
+	o.Erg = addSaturated(o.Erg, other.Erg)
+	for id, amount := range other.Tokens {
+		if o.Tokens == nil {
+			o.Tokens = make(map[string]int64)
+		}
+		o.Tokens[id] = addSaturated(o.Tokens[id], amount)
+	}
+}
+
+// addSaturated adds a and b, a sum beyond the limits of int64 violates any limit anyway
+func addSaturated(a int64, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+// destination is an output that leaves the addresses of the signer
+type destination struct {
+	tree         string
+	templateHash string
+}
+
+type policy struct {
+	config       PolicyConfig
+	allowedTrees map[string]bool
+}
+
+func newPolicy(config PolicyConfig) (*policy, error) {
+	if config.MaxErgPerTx == 0 || config.MaxErgPerDay == 0 {
+		return nil, fmt.Errorf("maxErgPerTx and maxErgPerDay are required, use %d to disable them", Unlimited)
+	}
+	limits := []int64{config.MaxErgPerTx, config.MaxErgPerDay}
+	for _, limit := range config.MaxTokensPerTx {
+		limits = append(limits, limit)
+	}
+	for _, limit := range config.MaxTokensPerDay {
+		limits = append(limits, limit)
+	}
+	if slices.ContainsFunc(limits, func(limit int64) bool { return limit < 0 && limit != Unlimited }) {
+		return nil, fmt.Errorf("limits must not be negative, use %d to disable them", Unlimited)
+	}
+
+	allowedTrees := map[string]bool{minerFeeTree: true}
+	for _, a := range config.AllowedAddresses {
+		address, err := ergo.NewAddress(a)
+		if err != nil {
+			return nil, fmt.Errorf("allowed address %s: %w", a, err)
+		}
+		tree := address.Tree()
+		treeStr, err := tree.Base16()
+		tree.Close()
+		address.Close()
+		if err != nil {
+			return nil, err
+		}
+		allowedTrees[treeStr] = true
+	}
+	return &policy{config: config, allowedTrees: allowedTrees}, nil
+}
+
+// disabledLimits returns the names of the limits set to Unlimited
+func (p *policy) disabledLimits() []string {
+	var disabled []string
+	if p.config.MaxErgPerTx == Unlimited {
+		disabled = append(disabled, "maxErgPerTx")
+	}
+	if p.config.MaxErgPerDay == Unlimited {
+		disabled = append(disabled, "maxErgPerDay")
+	}
+	for id, limit := range p.config.MaxTokensPerTx {
+		if limit == Unlimited {
+			disabled = append(disabled, "maxTokensPerTx of "+id)
+		}
+	}
+	for id, limit := range p.config.MaxTokensPerDay {
+		if limit == Unlimited {
+			disabled = append(disabled, "maxTokensPerDay of "+id)
+		}
+	}
+	slices.Sort(disabled)
+	return disabled
+}
+
+// check returns why a transaction with destinations and txOutflow violates the policy, given the outflow of the
+// transactions signed in the last 24 hours, or an empty string if it passes
+func (p *policy) check(destinations []destination, txOutflow outflow, dayOutflow outflow) string {
+	for _, d := range destinations {
+		if !p.allowedTrees[d.tree] && !slices.Contains(p.config.AllowedTemplateHashes, d.templateHash) {
+			return fmt.Sprintf("destination %s is not allowed", d.tree)
+		}
+	}
+
+	if exceeds(txOutflow.Erg, p.config.MaxErgPerTx) {
+		return fmt.Sprintf("erg outflow %d exceeds the limit per transaction of %d", txOutflow.Erg, p.config.MaxErgPerTx)
+	}
+	if exceeds(addSaturated(dayOutflow.Erg, txOutflow.Erg), p.config.MaxErgPerDay) {
+		return fmt.Sprintf("erg outflow %d exceeds the remaining daily limit of %d", txOutflow.Erg, p.config.MaxErgPerDay-dayOutflow.Erg)
+	}
+	for id, amount := range txOutflow.Tokens {
+		if exceeds(amount, p.config.MaxTokensPerTx[id]) {
+			return fmt.Sprintf("outflow %d of token %s exceeds the limit per transaction of %d", amount, id, p.config.MaxTokensPerTx[id])
+		}
+		if exceeds(addSaturated(dayOutflow.Tokens[id], amount), p.config.MaxTokensPerDay[id]) {
+			return fmt.Sprintf("outflow %d of token %s exceeds the remaining daily limit of %d", amount, id, p.config.MaxTokensPerDay[id]-dayOutflow.Tokens[id])
+		}
+	}
+	return ""
+}
+
+// exceeds reports whether amount is above limit, a limit of 0 allows no outflow at all
+func exceeds(amount int64, limit int64) bool {
+	return limit != Unlimited && amount > limit
+}
+
+// ledger keeps the outflow of the transactions signed in the last 24 hours
+type ledger struct {
+	mu      sync.Mutex
+	entries []*ledgerEntry
+}
+
+type ledgerEntry struct {
+	time    time.Time
+	outflow outflow
+}
+
+const ledgerWindow = 24 * time.Hour
+
+// total returns the outflow of the transactions signed in the 24 hours before now
+func (l *ledger) total(now time.Time) outflow {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = slices.DeleteFunc(l.entries, func(e *ledgerEntry) bool { return now.Sub(e.time) >= ledgerWindow })
+	var total outflow
+	for _, e := range l.entries {
+		total.add(e.outflow)
+	}
+	return total
+}
+
+// add adds the outflow o at t, the returned entry can be passed to release
+func (l *ledger) add(t time.Time, o outflow) *ledgerEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := &ledgerEntry{time: t, outflow: o}
+	l.entries = append(l.entries, e)
+	return e
+}
+
+// release removes e added by add, e.g. when a transaction was not signed after all
+func (l *ledger) release(e *ledgerEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = slices.DeleteFunc(l.entries, func(other *ledgerEntry) bool { return other == e })
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	allowed := "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r"
+	p, err := newPolicy(PolicyConfig{
+		AllowedAddresses:      []string{allowed},
+		AllowedTemplateHashes: []string{"0123"},
+		MaxErgPerTx:           100,
+		MaxErgPerDay:          150,
+		MaxTokensPerTx:        map[string]int64{"aa": 10},
+		MaxTokensPerDay:       map[string]int64{"aa": 15},
+	})
+	assert.NoError(t, err)
+	allowedTree := ""
+	for tree := range p.allowedTrees {
+		if tree != minerFeeTree {
+			allowedTree = tree
+		}
+	}
+
+	toAllowed := []destination{{tree: allowedTree}, {tree: minerFeeTree}}
+	assert.Empty(t, p.check(toAllowed, outflow{Erg: 100, Tokens: map[string]int64{"aa": 10}}, outflow{}))
+	assert.Empty(t, p.check([]destination{{tree: "0008cd", templateHash: "0123"}}, outflow{Erg: 1}, outflow{}))
+	assert.Contains(t, p.check([]destination{{tree: "0008cd", templateHash: "4567"}}, outflow{Erg: 1}, outflow{}), "destination 0008cd is not allowed")
+
+	assert.Contains(t, p.check(toAllowed, outflow{Erg: 101}, outflow{}), "limit per transaction")
+	assert.Contains(t, p.check(toAllowed, outflow{Erg: 60}, outflow{Erg: 100}), "remaining daily limit of 50")
+	assert.Contains(t, p.check(toAllowed, outflow{Tokens: map[string]int64{"aa": 11}}, outflow{}), "token aa exceeds the limit per transaction")
+	assert.Contains(t, p.check(toAllowed, outflow{Tokens: map[string]int64{"aa": 6}}, outflow{Tokens: map[string]int64{"aa": 10}}), "remaining daily limit of 5")
+	// tokens without a limit cannot be sent
+	assert.Contains(t, p.check(toAllowed, outflow{Tokens: map[string]int64{"bb": 1}}, outflow{}), "token bb exceeds the limit per transaction of 0")
+	// a saturated sum still violates the limit
+	assert.NotEmpty(t, p.check(toAllowed, outflow{Erg: 1}, outflow{Erg: math.MaxInt64}))
+
+	_, err = newPolicy(PolicyConfig{AllowedAddresses: []string{"invalid"}, MaxErgPerTx: 1, MaxErgPerDay: 1})
+	assert.ErrorContains(t, err, "allowed address invalid")
+}
+
+func TestNewPolicy_Limits(t *testing.T) {
+	_, err := newPolicy(PolicyConfig{MaxErgPerTx: 100})
+	assert.ErrorContains(t, err, "maxErgPerTx and maxErgPerDay are required")
+	_, err = newPolicy(PolicyConfig{MaxErgPerTx: 100, MaxErgPerDay: -2})
+	assert.ErrorContains(t, err, "limits must not be negative")
+	_, err = newPolicy(PolicyConfig{MaxErgPerTx: 1, MaxErgPerDay: 1, MaxTokensPerDay: map[string]int64{"aa": -5}})
+	assert.ErrorContains(t, err, "limits must not be negative")
+
+	p, err := newPolicy(PolicyConfig{
+		MaxErgPerTx:     Unlimited,
+		MaxErgPerDay:    100,
+		MaxTokensPerTx:  map[string]int64{"bb": Unlimited, "aa": Unlimited},
+		MaxTokensPerDay: map[string]int64{"aa": Unlimited},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"maxErgPerTx", "maxTokensPerDay of aa", "maxTokensPerTx of aa", "maxTokensPerTx of bb"}, p.disabledLimits())
+
+	toFee := []destination{{tree: minerFeeTree}}
+	assert.Empty(t, p.check(toFee, outflow{Erg: 100, Tokens: map[string]int64{"aa": math.MaxInt64}}, outflow{}))
+	assert.Contains(t, p.check(toFee, outflow{Erg: 101}, outflow{}), "remaining daily limit of 100")
+	// bb has no daily limit
+	assert.Contains(t, p.check(toFee, outflow{Tokens: map[string]int64{"bb": 1}}, outflow{}), "token bb exceeds the remaining daily limit of 0")
+}
+
+func TestLedger_Total(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	l := &ledger{}
+	l.add(now.Add(-25*time.Hour), outflow{Erg: 1000})
+	l.add(now.Add(-23*time.Hour), outflow{Erg: 10, Tokens: map[string]int64{"aa": 1}})
+	l.add(now.Add(-time.Hour), outflow{Erg: 5, Tokens: map[string]int64{"aa": 2, "bb": 3}})
+
+	assert.Equal(t, outflow{Erg: 15, Tokens: map[string]int64{"aa": 3, "bb": 3}}, l.total(now))
+	assert.Len(t, l.entries, 2)
+	assert.Equal(t, outflow{Erg: 5, Tokens: map[string]int64{"aa": 2, "bb": 3}}, l.total(now.Add(2*time.Hour)))
+}
+
+func TestLedger_Release(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	l := &ledger{}
+	first := l.add(now, outflow{Erg: 10})
+	l.add(now, outflow{Erg: 5})
+	// an equal entry is not mistaken for the released one
+	l.add(now, outflow{Erg: 10})
+
+	l.release(first)
+	assert.Equal(t, outflow{Erg: 15}, l.total(now))
+	l.release(first)
+	assert.Equal(t, outflow{Erg: 15}, l.total(now))
+}
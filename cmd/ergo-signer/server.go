@@ -0,0 +1,247 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	ergo "github.com/sigmaspace-io/ergo-lib-go"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRequestSize limits the body of a sign request
+const maxRequestSize = 1 << 22
+
+// server signs the transactions POSTed in the protocol of ergo.RemoteSigner that pass the policy
+type server struct {
+	keys   *keyring
+	policy *policy
+	ledger *ledger
+	audit  *auditLog
+	node   *node
+	now    func() time.Time
+	// token is the bearer token a request has to carry, requests are not authenticated if it is empty
+	token string
+
+	// mu serializes checking the policy and reserving the outflow in the ledger, so concurrent requests cannot
+	// exceed the daily limits. Fetching the headers and signing run without it
+	mu sync.Mutex
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, ergo.RemoteSignResponse{Error: "method not allowed"})
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeResponse(w, http.StatusUnauthorized, ergo.RemoteSignResponse{Error: "unauthorized"})
+		return
+	}
+	entry := auditEntry{Remote: r.RemoteAddr}
+
+	var request ergo.RemoteSignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&request); err != nil {
+		s.fail(w, entry, http.StatusBadRequest, err)
+		return
+	}
+	unsignedTx, boxesToSpend, dataBoxes, err := request.Decode()
+	if err != nil {
+		s.fail(w, entry, http.StatusBadRequest, err)
+		return
+	}
+	defer unsignedTx.Close()
+	defer boxesToSpend.Close()
+	defer dataBoxes.Close()
+
+	txId := unsignedTx.TxId()
+	entry.TxId, err = txId.String()
+	txId.Close()
+	if err != nil {
+		s.fail(w, entry, http.StatusBadRequest, err)
+		return
+	}
+	destinations, txOutflow, err := summarize(unsignedTx, boxesToSpend, s.keys.ownTrees)
+	if err != nil {
+		s.fail(w, entry, http.StatusBadRequest, err)
+		return
+	}
+	entry.Outflow = &txOutflow
+
+	now := s.now()
+	reservation, reason := s.reserve(destinations, txOutflow, now)
+	if reason != "" {
+		entry.Time = now
+		entry.Decision = decisionRejected
+		entry.Reason = reason
+		if err := s.audit.append(entry); err != nil {
+			log.Printf("audit log: %v", err)
+		}
+		writeResponse(w, http.StatusForbidden, ergo.RemoteSignResponse{Error: reason})
+		return
+	}
+
+	tx, err := s.sign(r, unsignedTx, boxesToSpend, dataBoxes)
+	if err != nil {
+		s.ledger.release(reservation)
+		s.fail(w, entry, http.StatusUnprocessableEntity, err)
+		return
+	}
+	defer tx.Close()
+	txJson, err := tx.Json()
+	if err != nil {
+		s.ledger.release(reservation)
+		s.fail(w, entry, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the signed transaction is only handed out once the decision is durable
+	entry.Time = now
+	entry.Decision = decisionSigned
+	if err := s.audit.append(entry); err != nil {
+		s.ledger.release(reservation)
+		log.Printf("audit log: %v", err)
+		writeResponse(w, http.StatusInternalServerError, ergo.RemoteSignResponse{Error: "audit log unavailable"})
+		return
+	}
+	writeResponse(w, http.StatusOK, ergo.RemoteSignResponse{Tx: json.RawMessage(txJson)})
+}
+
+// authorized reports whether r carries the bearer token of the server in its Authorization header
+func (s *server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// reserve checks the transaction against the policy and, if it passes, adds its outflow to the ledger until
+// the reservation is released. It returns why the transaction violates the policy otherwise
+func (s *server) reserve(destinations []destination, txOutflow outflow, now time.Time) (*ledgerEntry, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reason := s.policy.check(destinations, txOutflow, s.ledger.total(now)); reason != "" {
+		return nil, reason
+	}
+	return s.ledger.add(now, txOutflow), ""
+}
+
+// sign reduces unsignedTx with the StateContext of the node and signs the ReducedTransaction
+func (s *server) sign(r *http.Request, unsignedTx ergo.UnsignedTransaction, boxesToSpend ergo.Boxes, dataBoxes ergo.Boxes) (ergo.Transaction, error) {
+	stateContext, err := s.node.stateContext(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer stateContext.Close()
+	reducedTx, err := ergo.NewReducedTransactionContext(r.Context(), unsignedTx, boxesToSpend, dataBoxes, stateContext)
+	if err != nil {
+		return nil, err
+	}
+	defer reducedTx.Close()
+
+	wallet, err := s.keys.wallet(boxesToSpend)
+	if err != nil {
+		return nil, err
+	}
+	defer wallet.Close()
+	return wallet.SignReducedTransactionContext(r.Context(), reducedTx)
+}
+
+// fail records a request that could not be signed and responds with status
+func (s *server) fail(w http.ResponseWriter, entry auditEntry, status int, err error) {
+	entry.Time = s.now()
+	entry.Decision = decisionFailed
+	entry.Reason = err.Error()
+	if err := s.audit.append(entry); err != nil {
+		log.Printf("audit log: %v", err)
+	}
+	writeResponse(w, status, ergo.RemoteSignResponse{Error: err.Error()})
+}
+
+func writeResponse(w http.ResponseWriter, status int, response ergo.RemoteSignResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// summarize returns the outputs of unsignedTx that do not go to ownTrees and the outflow of the transaction.
+// Tokens of boxesToSpend that are not in any output are burned and count as outflow. A token with the id of
+// the first input is minted by the transaction, it counts as outflow where it leaves ownTrees
+func summarize(unsignedTx ergo.UnsignedTransaction, boxesToSpend ergo.Boxes, ownTrees map[string]bool) ([]destination, outflow, error) {
+	var destinations []destination
+	var txOutflow outflow
+	var mintedId string
+	remaining := make(map[string]int64)
+	for i, box := range boxesToSpend.All() {
+		if i == 0 {
+			boxId := box.BoxId()
+			mintedId = boxId.Base16()
+			boxId.Close()
+		}
+		addTokens(remaining, box.Tokens(), 1)
+		box.Close()
+	}
+
+	outputs := unsignedTx.OutputCandidates()
+	defer outputs.Close()
+	for _, candidate := range outputs.All() {
+		d, err := candidateDestination(candidate)
+		if err != nil {
+			candidate.Close()
+			return nil, outflow{}, err
+		}
+		tokens := make(map[string]int64)
+		addTokens(tokens, candidate.Tokens(), 1)
+		addTokens(remaining, candidate.Tokens(), -1)
+		if !ownTrees[d.tree] {
+			destinations = append(destinations, d)
+			value := candidate.BoxValue()
+			txOutflow.add(outflow{Erg: value.Int64(), Tokens: tokens})
+			value.Close()
+		}
+		candidate.Close()
+	}
+
+	for id, amount := range remaining {
+		if amount < 0 && id != mintedId {
+			return nil, outflow{}, fmt.Errorf("outputs contain more of token %s than the inputs", id)
+		}
+		if amount > 0 {
+			txOutflow.add(outflow{Tokens: map[string]int64{id: amount}})
+		}
+	}
+	return destinations, txOutflow, nil
+}
+
+func candidateDestination(candidate ergo.BoxCandidate) (destination, error) {
+	tree := candidate.Tree()
+	defer tree.Close()
+	treeStr, err := tree.Base16()
+	if err != nil {
+		return destination{}, err
+	}
+	templateHash, err := tree.TemplateHash()
+	if err != nil {
+		return destination{}, err
+	}
+	return destination{tree: treeStr, templateHash: templateHash}, nil
+}
+
+// addTokens adds the amounts of tokens multiplied by sign to amounts and closes tokens
+func addTokens(amounts map[string]int64, tokens ergo.Tokens, sign int64) {
+	defer tokens.Close()
+	for _, t := range tokens.All() {
+		tokenId := t.Id()
+		amount := t.Amount()
+		amounts[tokenId.Base16()] += sign * amount.Int64()
+		tokenId.Close()
+		amount.Close()
+		t.Close()
+	}
+}
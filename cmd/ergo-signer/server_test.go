@@ -0,0 +1,454 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	ergo "github.com/sigmaspace-io/ergo-lib-go"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// the addresses 0 and 1 of account 0 of testMnemonic
+	testOwnAddress    = "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r"
+	testChangeAddress = "9gYRhhA9TcFv6xWGwTBPLBJzyW1Hv3EiDzXqoivWYjq8TowWJ1h"
+	testOtherAddress  = "9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ"
+	testMnemonic      = "chef hidden swift slush bar length outdoor pupil hunt country endorse accuse"
+	testTokenA        = "03faf2cb329f2e90d6d23b58d91bbb6c046aa143261cc21f52fbe2824bfcbf04"
+	testTokenB        = "0cd8c9f416e5b1ca9f986a7f10a84191dfb85941619e49e53c0dc30ebf83324b"
+	testFee           = 1000000
+)
+
+var testKeystoreParams = ergo.KeystoreParams{Prf: ergo.KeystorePBKDF2, C: 1, DkLen: 256}
+
+const testBlockHeaderJson = `{
+	"extensionId": "d16f25b14457186df4c5f6355579cc769261ce1aebc8209949ca6feadbac5a3f",
+	"difficulty": "626412390187008",
+	"votes": "040000",
+	"timestamp": 1618929697400,
+	"size": 221,
+	"stateRoot": "8ad868627ea4f7de6e2a2fe3f98fafe57f914e0f2ef3331c006def36c697f92713",
+	"height": 471746,
+	"nBits": 117586360,
+	"version": 2,
+	"id": "4caa17e62fe66ba7bd69597afdc996ae35b1ff12e0ba90c22ff288a4de10e91b",
+	"adProofsRoot": "d882aaf42e0a95eb95fcce5c3705adf758e591532f733efe790ac3c404730c39",
+	"transactionsRoot": "63eaa9aff76a1de3d71c81e4b2d92e8d97ae572a8e9ab9e66599ed0912dd2f8b",
+	"extensionHash": "3f91f3c680beb26615fdec251aee3f81aaf5a02740806c167c0f3c929471df44",
+	"powSolutions": {
+		"pk": "02b3a06d6eaa8671431ba1db4dd427a77f75a5c2acbd71bfb725d38adc2b55f669",
+		"w": "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		"n": "5939ecfee6b0d7f4",
+		"d": 0
+	},
+	"adProofsId": "86eaa41f328bee598e33e52c9e515952ad3b7874102f762847f17318a776a7ae",
+	"transactionsId": "ac80245714f25aa2fafe5494ad02a26d46e7955b8f5709f3659f1b9440797b3e",
+	"parentId": "6481752bace5fa5acba5d5ef7124d48826664742d46c974c98a2d60ace229a34"
+}`
+
+// testOutput is an output of testUnsignedTx
+type testOutput struct {
+	tree   string
+	value  int64
+	tokens map[string]int64
+}
+
+func testTree(t *testing.T, address string) string {
+	a, err := ergo.NewAddress(address)
+	assert.NoError(t, err)
+	defer a.Close()
+	tree := a.Tree()
+	defer tree.Close()
+	treeStr, err := tree.Base16()
+	assert.NoError(t, err)
+	return treeStr
+}
+
+func testInput(t *testing.T, address string, value int64, tokens map[string]int64) ergo.Box {
+	a, err := ergo.NewAddress(address)
+	assert.NoError(t, err)
+	contract, err := ergo.NewContractPayToAddress(a)
+	assert.NoError(t, err)
+	boxValue, err := ergo.NewBoxValue(value)
+	assert.NoError(t, err)
+	txId, err := ergo.NewTxId("93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38")
+	assert.NoError(t, err)
+	boxTokens := ergo.NewTokens()
+	for id, amount := range tokens {
+		tokenId, err := ergo.NewTokenId(id)
+		assert.NoError(t, err)
+		tokenAmount, err := ergo.NewTokenAmount(amount)
+		assert.NoError(t, err)
+		boxTokens.Add(ergo.NewToken(tokenId, tokenAmount))
+	}
+	box, err := ergo.NewBox(boxValue, 0, contract, txId, 0, boxTokens)
+	assert.NoError(t, err)
+	return box
+}
+
+func testBoxId(box ergo.Box) string {
+	boxId := box.BoxId()
+	defer boxId.Close()
+	return boxId.Base16()
+}
+
+// testUnsignedTx returns the transaction spending input to outputs and the miner fee
+func testUnsignedTx(t *testing.T, input ergo.Box, outputs ...testOutput) ergo.UnsignedTransaction {
+	outputs = append(outputs, testOutput{tree: minerFeeTree, value: testFee})
+	outputsJson := make([]map[string]any, 0, len(outputs))
+	for _, o := range outputs {
+		assets := make([]map[string]any, 0, len(o.tokens))
+		for id, amount := range o.tokens {
+			assets = append(assets, map[string]any{"tokenId": id, "amount": amount})
+		}
+		outputsJson = append(outputsJson, map[string]any{
+			"value":               o.value,
+			"ergoTree":            o.tree,
+			"creationHeight":      471746,
+			"assets":              assets,
+			"additionalRegisters": map[string]string{},
+		})
+	}
+	txJson, err := json.Marshal(map[string]any{
+		"inputs":     []map[string]any{{"boxId": testBoxId(input), "extension": map[string]string{}}},
+		"dataInputs": []any{},
+		"outputs":    outputsJson,
+	})
+	assert.NoError(t, err)
+	unsignedTx, err := ergo.NewUnsignedTransactionFromJson(string(txJson))
+	assert.NoError(t, err)
+	return unsignedTx
+}
+
+func testBoxes(boxes ...ergo.Box) ergo.Boxes {
+	result := ergo.NewBoxes()
+	for _, box := range boxes {
+		result.Add(box)
+	}
+	return result
+}
+
+func testKeyring(t *testing.T) *keyring {
+	keystore, err := ergo.NewKeystoreFromMnemonic(testMnemonic, "", "password", testKeystoreParams)
+	assert.NoError(t, err)
+	keys, err := unlockKeyring(keystore, "password", 0, 2)
+	assert.NoError(t, err)
+	return keys
+}
+
+func TestSummarize(t *testing.T) {
+	ownTrees := map[string]bool{testTree(t, testOwnAddress): true, testTree(t, testChangeAddress): true}
+	otherTree := testTree(t, testOtherAddress)
+	input := testInput(t, testOwnAddress, 1000000000, map[string]int64{testTokenA: 100, testTokenB: 5})
+	inputs := testBoxes(input)
+
+	unsignedTx := testUnsignedTx(t, input,
+		testOutput{tree: otherTree, value: 100000000, tokens: map[string]int64{testTokenA: 10}},
+		testOutput{tree: testTree(t, testChangeAddress), value: 1000000000 - 100000000 - testFee, tokens: map[string]int64{testTokenA: 80}},
+	)
+	destinations, txOutflow, err := summarize(unsignedTx, inputs, ownTrees)
+	assert.NoError(t, err)
+	// the change is not a destination, the recipient and the fee are
+	assert.Len(t, destinations, 2)
+	assert.Equal(t, otherTree, destinations[0].tree)
+	assert.Equal(t, minerFeeTree, destinations[1].tree)
+	// 10 of token A are sent, 10 of token A and all of token B are burned
+	assert.Equal(t, outflow{Erg: 100000000 + testFee, Tokens: map[string]int64{testTokenA: 20, testTokenB: 5}}, txOutflow)
+}
+
+func TestSummarize_Mint(t *testing.T) {
+	ownTree := testTree(t, testOwnAddress)
+	ownTrees := map[string]bool{ownTree: true}
+	input := testInput(t, testOwnAddress, 1000000000, nil)
+	inputs := testBoxes(input)
+	mintedId := testBoxId(input)
+
+	unsignedTx := testUnsignedTx(t, input,
+		testOutput{tree: ownTree, value: 1000000000 - testFee, tokens: map[string]int64{mintedId: 1000}},
+	)
+	destinations, txOutflow, err := summarize(unsignedTx, inputs, ownTrees)
+	assert.NoError(t, err)
+	assert.Len(t, destinations, 1)
+	assert.Equal(t, outflow{Erg: testFee}, txOutflow)
+
+	// a minted token sent away is outflow
+	otherTree := testTree(t, testOtherAddress)
+	unsignedTx = testUnsignedTx(t, input,
+		testOutput{tree: otherTree, value: 1000000000 - testFee, tokens: map[string]int64{mintedId: 1000}},
+	)
+	_, txOutflow, err = summarize(unsignedTx, inputs, ownTrees)
+	assert.NoError(t, err)
+	assert.Equal(t, outflow{Erg: 1000000000, Tokens: map[string]int64{mintedId: 1000}}, txOutflow)
+
+	// only the id of the first input can be minted
+	unsignedTx = testUnsignedTx(t, input,
+		testOutput{tree: ownTree, value: 1000000000 - testFee, tokens: map[string]int64{testTokenA: 1}},
+	)
+	_, _, err = summarize(unsignedTx, inputs, ownTrees)
+	assert.ErrorContains(t, err, fmt.Sprintf("outputs contain more of token %s than the inputs", testTokenA))
+}
+
+// testSigner returns a server behind httptest with a fake node serving the last block headers
+func testSigner(t *testing.T, config PolicyConfig, now time.Time) (*server, *httptest.Server) {
+	nodeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fmt.Sprintf("/blocks/lastHeaders/%d", stateContextHeaders) {
+			http.NotFound(w, r)
+			return
+		}
+		headers := make([]string, stateContextHeaders)
+		for i := range headers {
+			headers[i] = testBlockHeaderJson
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(headers, ",") + "]"))
+	}))
+	t.Cleanup(nodeServer.Close)
+
+	p, err := newPolicy(config)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	audit, _, err := openAuditLog(filepath.Join(dir, "audit.log"), filepath.Join(dir, "audit.log.head"))
+	assert.NoError(t, err)
+	t.Cleanup(func() { audit.Close() })
+
+	s := &server{
+		keys:   testKeyring(t),
+		policy: p,
+		ledger: &ledger{},
+		audit:  audit,
+		node:   &node{url: nodeServer.URL, client: nodeServer.Client()},
+		now:    func() time.Time { return now },
+	}
+	signerServer := httptest.NewServer(s)
+	t.Cleanup(signerServer.Close)
+	return s, signerServer
+}
+
+func TestServer(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	s, signerServer := testSigner(t, PolicyConfig{
+		AllowedAddresses: []string{testOtherAddress},
+		MaxErgPerTx:      200000000,
+		MaxErgPerDay:     150000000,
+	}, now)
+	signer := ergo.NewRemoteSigner(signerServer.URL)
+
+	input := testInput(t, testOwnAddress, 1000000000, nil)
+	inputs := testBoxes(input)
+	dataInputs := ergo.NewBoxes()
+
+	// a destination outside the policy
+	stranger := ergo.NewSecretKey().Address()
+	strangerTree := stranger.Tree()
+	strangerTreeStr, err := strangerTree.Base16()
+	assert.NoError(t, err)
+	unsignedTx := testUnsignedTx(t, input,
+		testOutput{tree: strangerTreeStr, value: 100000000},
+		testOutput{tree: testTree(t, testOwnAddress), value: 1000000000 - 100000000 - testFee},
+	)
+	_, err = signer.SignTransaction(nil, unsignedTx, inputs, dataInputs)
+	assert.ErrorContains(t, err, fmt.Sprintf("destination %s is not allowed", strangerTreeStr))
+
+	unsignedTx = testUnsignedTx(t, input,
+		testOutput{tree: testTree(t, testOtherAddress), value: 100000000},
+		testOutput{tree: testTree(t, testOwnAddress), value: 1000000000 - 100000000 - testFee},
+	)
+	tx, err := signer.SignTransaction(nil, unsignedTx, inputs, dataInputs)
+	assert.NoError(t, err)
+	txId := tx.TxId()
+	txIdStr, err := txId.String()
+	assert.NoError(t, err)
+	unsignedTxId := unsignedTx.TxId()
+	unsignedTxIdStr, err := unsignedTxId.String()
+	assert.NoError(t, err)
+	assert.Equal(t, unsignedTxIdStr, txIdStr)
+
+	// the same transaction again exceeds the daily limit
+	_, err = signer.SignTransaction(nil, unsignedTx, inputs, dataInputs)
+	assert.ErrorContains(t, err, "exceeds the remaining daily limit of 49000000")
+
+	// other methods are refused without an audit entry
+	response, err := http.Get(signerServer.URL)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+
+	assert.NoError(t, s.audit.Close())
+	_, entries, err := openAuditLog(s.audit.file.Name(), s.audit.headPath)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, decisionRejected, entries[0].Decision)
+	assert.Equal(t, decisionSigned, entries[1].Decision)
+	assert.Equal(t, txIdStr, entries[1].TxId)
+	assert.Equal(t, &outflow{Erg: 100000000 + testFee}, entries[1].Outflow)
+	assert.Equal(t, decisionRejected, entries[2].Decision)
+
+	// the daily limit holds across a restart
+	assert.Equal(t, outflow{Erg: 100000000 + testFee}, replayLedger(entries, now).total(now))
+}
+
+func TestServer_AuditUnavailable(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	s, signerServer := testSigner(t, PolicyConfig{MaxErgPerTx: Unlimited, MaxErgPerDay: Unlimited}, now)
+
+	input := testInput(t, testOwnAddress, 1000000000, nil)
+	unsignedTx := testUnsignedTx(t, input,
+		testOutput{tree: testTree(t, testOwnAddress), value: 1000000000 - testFee},
+	)
+	// the signed transaction is withheld if its audit entry cannot be written
+	assert.NoError(t, s.audit.Close())
+	_, err := ergo.NewRemoteSigner(signerServer.URL).SignTransaction(nil, unsignedTx, testBoxes(input), ergo.NewBoxes())
+	assert.ErrorContains(t, err, "audit log unavailable")
+	assert.Equal(t, outflow{}, s.ledger.total(now))
+}
+
+// bearerTransport adds the bearer token to the requests
+type bearerTransport struct {
+	token string
+}
+
+func (b bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestServer_Token(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	s, signerServer := testSigner(t, PolicyConfig{MaxErgPerTx: Unlimited, MaxErgPerDay: Unlimited}, now)
+	s.token = "secret"
+
+	input := testInput(t, testOwnAddress, 1000000000, nil)
+	inputs := testBoxes(input)
+	unsignedTx := testUnsignedTx(t, input,
+		testOutput{tree: testTree(t, testOwnAddress), value: 1000000000 - testFee},
+	)
+	_, err := ergo.NewRemoteSigner(signerServer.URL).SignTransaction(nil, unsignedTx, inputs, ergo.NewBoxes())
+	assert.ErrorContains(t, err, "unauthorized")
+	wrongToken := &ergo.RemoteSigner{URL: signerServer.URL, Client: &http.Client{Transport: bearerTransport{token: "secreT"}}}
+	_, err = wrongToken.SignTransaction(nil, unsignedTx, inputs, ergo.NewBoxes())
+	assert.ErrorContains(t, err, "unauthorized")
+	// rejected requests are not audited
+	assert.Equal(t, 0, s.audit.entries)
+
+	signer := &ergo.RemoteSigner{URL: signerServer.URL, Client: &http.Client{Transport: bearerTransport{token: "secret"}}}
+	tx, err := signer.SignTransaction(nil, unsignedTx, inputs, ergo.NewBoxes())
+	assert.NoError(t, err)
+	tx.Close()
+}
+
+func TestListen_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := listen(unixPrefix + path)
+	assert.NoError(t, err)
+	defer listener.Close()
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.Equal(t, "unix", listener.Addr().Network())
+}
+
+func TestServer_SlowNode(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	s, signerServer := testSigner(t, PolicyConfig{
+		AllowedAddresses: []string{testOtherAddress},
+		MaxErgPerTx:      200000000,
+		MaxErgPerDay:     150000000,
+	}, now)
+	nodeRequested := make(chan struct{})
+	unblock := make(chan struct{})
+	slowNode := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(nodeRequested)
+		<-unblock
+		http.Error(w, "node unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(slowNode.Close)
+	release := sync.OnceFunc(func() { close(unblock) })
+	t.Cleanup(release)
+	s.node = &node{url: slowNode.URL, client: slowNode.Client()}
+
+	input := testInput(t, testOwnAddress, 1000000000, nil)
+	inputs := testBoxes(input)
+	unsignedTx := testUnsignedTx(t, input,
+		testOutput{tree: testTree(t, testOtherAddress), value: 100000000},
+		testOutput{tree: testTree(t, testOwnAddress), value: 1000000000 - 100000000 - testFee},
+	)
+	signer := ergo.NewRemoteSigner(signerServer.URL)
+	done := make(chan error, 1)
+	go func() {
+		_, err := signer.SignTransaction(nil, unsignedTx, inputs, ergo.NewBoxes())
+		done <- err
+	}()
+	<-nodeRequested
+
+	// while the first request waits for the node, the second one is decided against the reserved outflow
+	_, err := signer.SignTransaction(nil, unsignedTx, inputs, ergo.NewBoxes())
+	assert.ErrorContains(t, err, "exceeds the remaining daily limit of 49000000")
+
+	release()
+	assert.ErrorContains(t, <-done, "503")
+	// the outflow of the transaction that was not signed is released
+	assert.Equal(t, outflow{}, s.ledger.total(now))
+}
+
+func TestReplayLedger(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	entries := []auditEntry{
+		{Time: now.Add(-25 * time.Hour), Decision: decisionSigned, Outflow: &outflow{Erg: 1000}},
+		{Time: now.Add(-23 * time.Hour), Decision: decisionSigned, Outflow: &outflow{Erg: 10, Tokens: map[string]int64{"aa": 1}}},
+		{Time: now.Add(-2 * time.Hour), Decision: decisionRejected, Outflow: &outflow{Erg: 100}},
+		{Time: now.Add(-2 * time.Hour), Decision: decisionFailed, Outflow: &outflow{Erg: 100}},
+		{Time: now.Add(-time.Hour), Decision: decisionSigned, Outflow: &outflow{Erg: 5}},
+	}
+
+	l := replayLedger(entries, now)
+	assert.Equal(t, outflow{Erg: 15, Tokens: map[string]int64{"aa": 1}}, l.total(now))
+	assert.Equal(t, outflow{Erg: 5}, l.total(now.Add(2*time.Hour)))
+}
+
+func TestUnlockKeyring(t *testing.T) {
+	keystore, err := ergo.NewKeystoreFromMnemonic(testMnemonic, "", "password", testKeystoreParams)
+	assert.NoError(t, err)
+	_, err = unlockKeyring(keystore, "wrong", 0, 2)
+	assert.ErrorIs(t, err, ergo.ErrWrongPassword)
+
+	keys, err := unlockKeyring(keystore, "password", 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{testTree(t, testOwnAddress): true, testTree(t, testChangeAddress): true}, keys.ownTrees)
+
+	wallet, err := keys.wallet(testBoxes(testInput(t, testChangeAddress, 1000000000, nil)))
+	assert.NoError(t, err)
+	wallet.Close()
+	_, err = keys.wallet(testBoxes(testInput(t, testOtherAddress, 1000000000, nil)))
+	assert.ErrorContains(t, err, "not a box of the signer")
+
+	// a seed that cannot be unlocked is not mistaken for secret keys
+	keystore.UsePre1627KeyDerivation = true
+	_, err = unlockKeyring(keystore, "password", 0, 2)
+	assert.ErrorContains(t, err, "pre 1627 key derivation")
+}
+
+func TestUnlockKeyring_SecretKeys(t *testing.T) {
+	secret := ergo.NewSecretKey()
+	address := secret.Address()
+	addressStr := address.Base58(ergo.MainnetPrefix)
+	secrets := ergo.NewSecretKeys()
+	secrets.Add(secret)
+	keystore, err := ergo.NewKeystoreFromSecretKeys(secrets, "password", testKeystoreParams)
+	assert.NoError(t, err)
+
+	_, err = unlockKeyring(keystore, "wrong", 0, 2)
+	assert.ErrorIs(t, err, ergo.ErrWrongPassword)
+
+	keys, err := unlockKeyring(keystore, "password", 0, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, keys.account)
+	assert.Equal(t, 1, keys.secrets.Len())
+	assert.Equal(t, map[string]bool{testTree(t, addressStr): true}, keys.ownTrees)
+}
@@ -0,0 +1,110 @@
+package ergo
+
+import (
+	"errors"
+	"iter"
+	"math"
+)
+
+// Collection is implemented by the ordered collections of this package, e.g. Boxes, Tokens,
+// BoxCandidates, DataInputs, BlockHeaders or SecretKeys
+type Collection[T any] interface {
+	// Len returns the length of the collection
+	Len() int
+	// Get returns the element at the provided index if it exists
+	Get(index int) (T, error)
+	// Add adds provided element to the end of the collection
+	Add(item T)
+	// All returns an iterator over all elements inside the collection
+	All() iter.Seq2[int, T]
+}
+
+var errSumOverflow = errors.New("sum overflows int64")
+
+// FromSlice creates a new collection using newCollection and adds all items to it in order, e.g.
+// FromSlice(NewBoxes, boxes)
+func FromSlice[C Collection[T], T any](newCollection func() C, items []T) C {
+	c := newCollection()
+	for _, item := range items {
+		c.Add(item)
+	}
+	return c
+}
+
+// ToSlice returns all elements of the collection as slice
+func ToSlice[T any](c Collection[T]) ([]T, error) {
+	length := c.Len()
+	items := make([]T, 0, length)
+	for i := 0; i < length; i++ {
+		item, err := c.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Filter returns all elements of the collection for which keep returns true
+func Filter[T any](c Collection[T], keep func(item T) bool) ([]T, error) {
+	var items []T
+	for i := 0; i < c.Len(); i++ {
+		item, err := c.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		if keep(item) {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// Find returns the first element of the collection for which match returns true or the zero value
+// (nil for the types of this package) if there is none
+func Find[T any](c Collection[T], match func(item T) bool) (T, error) {
+	var zero T
+	for i := 0; i < c.Len(); i++ {
+		item, err := c.Get(i)
+		if err != nil {
+			return zero, err
+		}
+		if match(item) {
+			return item, nil
+		}
+	}
+	return zero, nil
+}
+
+// Sum adds up value for all elements of the collection and returns an error if the sum overflows int64, e.g.
+// Sum(boxes, func(b Box) int64 { return b.BoxValue().Int64() })
+func Sum[T any](c Collection[T], value func(item T) int64) (int64, error) {
+	var sum int64
+	for i := 0; i < c.Len(); i++ {
+		item, err := c.Get(i)
+		if err != nil {
+			return 0, err
+		}
+		v := value(item)
+		if (v > 0 && sum > math.MaxInt64-v) || (v < 0 && sum < math.MinInt64-v) {
+			return 0, errSumOverflow
+		}
+		sum += v
+	}
+	return sum, nil
+}
+
+var (
+	_ Collection[Box]            = Boxes(nil)
+	_ Collection[BoxCandidate]   = BoxCandidates(nil)
+	_ Collection[BoxAssetsData]  = BoxAssetsDataList(nil)
+	_ Collection[Token]          = Tokens(nil)
+	_ Collection[DataInput]      = DataInputs(nil)
+	_ Collection[UnsignedInput]  = UnsignedInputs(nil)
+	_ Collection[Input]          = Inputs(nil)
+	_ Collection[BlockHeader]    = BlockHeaders(nil)
+	_ Collection[BlockId]        = BlockIds(nil)
+	_ Collection[SecretKey]      = SecretKeys(nil)
+	_ Collection[ByteArray]      = ByteArrays(nil)
+	_ Collection[CommitmentHint] = HintsBag(nil)
+)
@@ -0,0 +1,63 @@
+package ergo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func testTokensSlice(amounts ...int64) []Token {
+	tokenId, _ := NewTokenId("19475d9a78377ff0f36e9826cec439727bea522f6ffa3bda32e20d2f8b3103ac")
+	var tokens []Token
+	for _, amount := range amounts {
+		tokenAmount, _ := NewTokenAmount(amount)
+		tokens = append(tokens, NewToken(tokenId, tokenAmount))
+	}
+	return tokens
+}
+
+func tokenAmountOf(t Token) int64 {
+	return t.Amount().Int64()
+}
+
+func TestFromSlice_ToSlice(t *testing.T) {
+	tokens := FromSlice(NewTokens, testTokensSlice(1, 2, 3))
+	assert.Equal(t, 3, tokens.Len())
+
+	slice, err := ToSlice[Token](tokens)
+	assert.NoError(t, err)
+	assert.Len(t, slice, 3)
+	assert.Equal(t, int64(2), slice[1].Amount().Int64())
+}
+
+func TestFilter(t *testing.T) {
+	tokens := FromSlice(NewTokens, testTokensSlice(1, 2, 3, 4))
+
+	even, err := Filter[Token](tokens, func(t Token) bool { return tokenAmountOf(t)%2 == 0 })
+	assert.NoError(t, err)
+	assert.Len(t, even, 2)
+	assert.Equal(t, int64(4), tokenAmountOf(even[1]))
+}
+
+func TestFind(t *testing.T) {
+	tokens := FromSlice(NewTokens, testTokensSlice(1, 2, 3))
+
+	found, err := Find[Token](tokens, func(t Token) bool { return tokenAmountOf(t) > 1 })
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), tokenAmountOf(found))
+
+	missing, err := Find[Token](tokens, func(t Token) bool { return tokenAmountOf(t) > 3 })
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestSum(t *testing.T) {
+	tokens := FromSlice(NewTokens, testTokensSlice(1, 2, 3))
+
+	sum, err := Sum[Token](tokens, tokenAmountOf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), sum)
+
+	_, err = Sum[Token](FromSlice(NewTokens, testTokensSlice(math.MaxInt64-1, 2)), tokenAmountOf)
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package ergo
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// ErrCommitmentReused is returned when commitments generated for one transaction are used to sign another one.
+// Signing two different messages with the same secret commitment reveals the secret key
+var ErrCommitmentReused = errors.New("ergo: commitment reused for a different transaction")
+
+// CommitmentStore records the transaction the commitments generated by a Wallet belong to. Once a Wallet has a
+// CommitmentStore set, GenerateCommitments binds the new commitments to the transaction and the multi signature
+// signing methods refuse hints taken from commitments that are bound to a different transaction.
+// Commitments are tracked through HintsBag, TransactionHintsBag and CommitmentHint values derived from the
+// TransactionHintsBag returned by GenerateCommitments.
+//
+// A CommitmentHint added to a HintsBag with Add keeps its own commitment ids, so hints of different
+// transactions can be collected in one bag. ergo-lib-c orders the hints of an input of a TransactionHintsBag by
+// their kind though, so every hint of the HintsBag returned by AllHintsForInput carries the ids of all
+// commitments of that input, and signing with any of them binds all of them
+type CommitmentStore interface {
+	// Bind binds the commitments identified by id to txId. It returns ErrCommitmentReused if id is already
+	// bound to a different transaction
+	Bind(id string, txId string) error
+}
+
+// MemoryCommitmentStore is a CommitmentStore that keeps the bindings in memory
+type MemoryCommitmentStore struct {
+	mu       sync.Mutex
+	bindings map[string]string
+}
+
+// NewMemoryCommitmentStore creates an empty MemoryCommitmentStore
+func NewMemoryCommitmentStore() *MemoryCommitmentStore {
+	return &MemoryCommitmentStore{bindings: make(map[string]string)}
+}
+
+func (m *MemoryCommitmentStore) Bind(id string, txId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return bind(m.bindings, id, txId)
+}
+
+// FileCommitmentStore is a CommitmentStore that appends the bindings to a file, one JSON object per line
+type FileCommitmentStore struct {
+	mu       sync.Mutex
+	file     *os.File
+	bindings map[string]string
+}
+
+type commitmentBinding struct {
+	Id   string `json:"id"`
+	TxId string `json:"txId"`
+}
+
+// NewFileCommitmentStore opens the FileCommitmentStore at path, the file is created if it does not exist
+func NewFileCommitmentStore(path string) (*FileCommitmentStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		var b commitmentBinding
+		if err := json.Unmarshal(scanner.Bytes(), &b); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("commitment store %s line %d: %w", path, line, err)
+		}
+		bindings[b.Id] = b.TxId
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileCommitmentStore{file: file, bindings: bindings}, nil
+}
+
+func (f *FileCommitmentStore) Bind(id string, txId string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return ErrClosed
+	}
+	if _, ok := f.bindings[id]; ok {
+		return bind(f.bindings, id, txId)
+	}
+
+	line, err := json.Marshal(commitmentBinding{Id: id, TxId: txId})
+	if err != nil {
+		return err
+	}
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	// the binding has to be durable before the commitments can be used
+	if err := f.file.Sync(); err != nil {
+		return err
+	}
+
+	return bind(f.bindings, id, txId)
+}
+
+// Close closes the underlying file
+func (f *FileCommitmentStore) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func bind(bindings map[string]string, id string, txId string) error {
+	if bound, ok := bindings[id]; ok && bound != txId {
+		return ErrCommitmentReused
+	}
+	bindings[id] = txId
+	return nil
+}
+
+// bindCommitments binds all commitment ids to txId, ids taken from another transaction make it fail
+func bindCommitments(store CommitmentStore, ids []string, txId TxId) error {
+	defer txId.Close()
+
+	if store == nil || len(ids) == 0 {
+		return nil
+	}
+
+	txIdStr, err := txId.String()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := store.Bind(id, txIdStr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newCommitmentId() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// mergeCommitmentIds returns the union of both id lists, a is not modified
+func mergeCommitmentIds(a []string, b []string) []string {
+	result := a
+	for _, id := range b {
+		if !slices.Contains(result, id) {
+			result = append(slices.Clip(result), id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package ergo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCommitmentStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commitments")
+
+	store, err := NewFileCommitmentStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Bind("a", "tx1"))
+	assert.NoError(t, store.Bind("a", "tx1"))
+	assert.ErrorIs(t, store.Bind("a", "tx2"), ErrCommitmentReused)
+	assert.NoError(t, store.Close())
+	assert.ErrorIs(t, store.Bind("b", "tx1"), ErrClosed)
+
+	reopened, err := NewFileCommitmentStore(path)
+	assert.NoError(t, err)
+	defer reopened.Close()
+	assert.ErrorIs(t, reopened.Bind("a", "tx2"), ErrCommitmentReused)
+	assert.NoError(t, reopened.Bind("b", "tx2"))
+}
+
+func TestWallet_SetCommitmentStore(t *testing.T) {
+	sk := NewSecretKey()
+	inputContract, _ := NewContractPayToAddress(sk.Address())
+	testTxId, _ := NewTxId("93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38")
+	inputBoxVal, _ := NewBoxValue(1000000000)
+	inputBox, _ := NewBox(inputBoxVal, 0, inputContract, testTxId, 0, NewTokens())
+	unspentBoxes := NewBoxes()
+	unspentBoxes.Add(inputBox)
+
+	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
+	testContract, _ := NewContractPayToAddress(recipient)
+	fee := SuggestedTxFee()
+	buildTx := func(value int64) UnsignedTransaction {
+		outBoxValue, _ := NewBoxValue(value)
+		outbox, _ := NewBoxCandidateBuilder(outBoxValue, testContract, 0).Build()
+		txOutputs := NewBoxCandidates()
+		txOutputs.Add(outbox)
+		targetBalance, _ := SumOfBoxValues(outBoxValue, fee)
+		testBoxSelection, _ := NewSimpleBoxSelector().Select(unspentBoxes, targetBalance, NewTokens())
+		tx, _ := NewTxBuilder(testBoxSelection, txOutputs, 0, fee, recipient).Build()
+		return tx
+	}
+	tx1 := buildTx(10000000)
+	tx2 := buildTx(20000000)
+
+	testBlockHeaders := testBlockHeadersFromJson()
+	testBlockHeader, _ := testBlockHeaders.Get(0)
+	ctx, _ := NewStateContext(NewPreHeader(testBlockHeader), testBlockHeaders, DefaultParameters())
+	testSecretKeys := NewSecretKeys()
+	testSecretKeys.Add(sk)
+	testWallet := NewWalletFromSecretKeys(testSecretKeys)
+	testWallet.SetCommitmentStore(NewMemoryCommitmentStore())
+
+	commitments, err := testWallet.GenerateCommitments(ctx, tx1, unspentBoxes, NewBoxes())
+	assert.NoError(t, err)
+
+	// hints copied into a new bag are still tracked
+	txHints := NewTransactionHintsBag()
+	txHints.AddHintsForInput(0, commitments.AllHintsForInput(0))
+
+	_, err = testWallet.SignTransactionMulti(ctx, tx2, unspentBoxes, NewBoxes(), txHints)
+	assert.ErrorIs(t, err, ErrCommitmentReused)
+
+	signedTx, err := testWallet.SignTransactionMulti(ctx, tx1, unspentBoxes, NewBoxes(), txHints)
+	assert.NoError(t, err)
+	assert.NoError(t, signedTx.Validate(ctx, unspentBoxes, NewBoxes()))
+
+	// hints collected from the commitments of two transactions keep their own ids
+	commitments2, err := testWallet.GenerateCommitments(ctx, tx2, unspentBoxes, NewBoxes())
+	assert.NoError(t, err)
+	hint1, _ := commitments.AllHintsForInput(0).Get(0)
+	hint2, _ := commitments2.AllHintsForInput(0).Get(0)
+	mixed := NewHintsBag()
+	mixed.Add(hint1)
+	mixed.Add(hint2)
+	mixedHint1, _ := mixed.Get(0)
+	mixedHint2, _ := mixed.Get(1)
+	assert.Equal(t, commitments.commitmentIds(), mixedHint1.commitmentIds())
+	assert.Equal(t, commitments2.commitmentIds(), mixedHint2.commitmentIds())
+	assert.NotEqual(t, mixedHint1.commitmentIds(), mixedHint2.commitmentIds())
+	assert.Len(t, mixed.commitmentIds(), 2)
+}
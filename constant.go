@@ -29,6 +29,8 @@ type Constant interface {
 	// Equals checks if provided Constant is same
 	Equals(constant Constant) bool
 	bytesLength() (int, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ConstantPtr
 }
 
@@ -121,6 +123,10 @@ func NewConstantFromBox(box Box) Constant {
 }
 
 func (c *constant) Base16() (string, error) {
+	if c.p == nil {
+		return "", ErrClosed
+	}
+
 	var constantStr *C.char
 
 	errPtr := C.ergo_lib_constant_to_base16(c.p, &constantStr)
@@ -135,6 +141,10 @@ func (c *constant) Base16() (string, error) {
 }
 
 func (c *constant) Type() (string, error) {
+	if c.p == nil {
+		return "", ErrClosed
+	}
+
 	var constantTypeStr *C.char
 
 	errPtr := C.ergo_lib_constant_type_to_dbg_str(c.p, &constantTypeStr)
@@ -149,6 +159,10 @@ func (c *constant) Type() (string, error) {
 }
 
 func (c *constant) Value() (string, error) {
+	if c.p == nil {
+		return "", ErrClosed
+	}
+
 	var constantValueStr *C.char
 
 	errPtr := C.ergo_lib_constant_value_to_dbg_str(c.p, &constantValueStr)
@@ -163,6 +177,10 @@ func (c *constant) Value() (string, error) {
 }
 
 func (c *constant) Int16() (int16, error) {
+	if c.p == nil {
+		return 0, ErrClosed
+	}
+
 	res := C.ergo_lib_constant_to_i16(c.p)
 	err := newError(res.error)
 	if err.isError() {
@@ -172,6 +190,10 @@ func (c *constant) Int16() (int16, error) {
 }
 
 func (c *constant) Int32() (int32, error) {
+	if c.p == nil {
+		return 0, ErrClosed
+	}
+
 	res := C.ergo_lib_constant_to_i32(c.p)
 	err := newError(res.error)
 	if err.isError() {
@@ -181,6 +203,10 @@ func (c *constant) Int32() (int32, error) {
 }
 
 func (c *constant) Int64() (int64, error) {
+	if c.p == nil {
+		return 0, ErrClosed
+	}
+
 	res := C.ergo_lib_constant_to_i64(c.p)
 	err := newError(res.error)
 	if err.isError() {
@@ -190,11 +216,19 @@ func (c *constant) Int64() (int64, error) {
 }
 
 func (c *constant) Equals(constant Constant) bool {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_constant_eq(c.p, constant.pointer())
 	return bool(res)
 }
 
 func (c *constant) bytesLength() (int, error) {
+	if c.p == nil {
+		return 0, ErrClosed
+	}
+
 	var returnNum C.ReturnNum_usize
 	returnNum = C.ergo_lib_constant_bytes_len(c.p)
 	err := newError(returnNum.error)
@@ -208,6 +242,10 @@ func (c *constant) bytesLength() (int, error) {
 }
 
 func (c *constant) Bytes() ([]byte, error) {
+	if c.p == nil {
+		return nil, ErrClosed
+	}
+
 	bytesLength, bytesLengthErr := c.bytesLength()
 	if bytesLengthErr != nil {
 		return []byte{}, bytesLengthErr
@@ -228,9 +266,21 @@ func (c *constant) Bytes() ([]byte, error) {
 }
 
 func (c *constant) pointer() C.ConstantPtr {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	return c.p
 }
 
+func (c *constant) Close() {
+	if c.p != nil {
+		runtime.SetFinalizer(c, nil)
+		finalizeConstant(c)
+		c.p = nil
+	}
+}
+
 func finalizeConstant(c *constant) {
 	C.ergo_lib_constant_delete(c.p)
 }
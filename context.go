@@ -0,0 +1,44 @@
+package ergo
+
+import "context"
+
+// withContext runs f on its own goroutine and returns its result, or ctx.Err() if ctx is done first.
+// Native calls cannot be interrupted, so f keeps running in the background after cancellation and
+// a successful result that arrives too late is handed to discard to release it. The lockers, the
+// arguments f passes to native code, are read locked until f returns, so closing them after a
+// cancelled call waits for f instead of freeing memory still in use. f must not lock them again
+func withContext[T any](ctx context.Context, lockers []locker, f func() (T, error), discard func(T)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1)
+	unlock := readLock(lockers...)
+	go func() {
+		value, err := f()
+		unlock()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-ctx.Done():
+		go func() {
+			res := <-done
+			if res.err == nil && discard != nil {
+				discard(res.value)
+			}
+		}()
+		return zero, ctx.Err()
+	}
+}
+
+func closeTransaction(t Transaction) {
+	t.Close()
+}
@@ -0,0 +1,76 @@
+package ergo
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWithContext(t *testing.T) {
+	value, err := withContext(context.Background(), nil, func() (int, error) {
+		return 42, nil
+	}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
+
+func TestWithContext_Deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	release := make(chan struct{})
+	discarded := make(chan int, 1)
+	_, err := withContext(ctx, nil, func() (int, error) {
+		<-release
+		return 42, nil
+	}, func(value int) {
+		discarded <- value
+	})
+	close(release)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 42, <-discarded)
+}
+
+func TestWithContext_CloseWaits(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	boxes := NewBoxes()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := withContext(ctx, []locker{boxes}, func() (int, error) {
+		close(started)
+		<-release
+		return 42, nil
+	}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	closed := make(chan struct{})
+	go func() {
+		boxes.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while the call was still running")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+}
+
+func TestWallet_SignReducedTransactionContext_Canceled(t *testing.T) {
+	wallet, _ := NewWallet("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "testPass")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := wallet.SignReducedTransactionContext(ctx, nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
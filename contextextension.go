@@ -7,6 +7,7 @@ import "C"
 import (
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -22,11 +23,15 @@ type ContextExtension interface {
 	All() iter.Seq2[uint8, Constant]
 	// Values returns iterator over all Constant in the ContextExtension
 	Values() iter.Seq[Constant]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ContextExtensionPtr
 }
 
 type contextExtension struct {
-	p C.ContextExtensionPtr
+	p  C.ContextExtensionPtr
+	mu sync.RWMutex
 }
 
 func newContextExtension(c *contextExtension) ContextExtension {
@@ -43,14 +48,14 @@ func NewContextExtension() ContextExtension {
 }
 
 func (c *contextExtension) Keys() iter.Seq[uint8] {
-	bytesLength := C.ergo_lib_context_extension_len(c.p)
-
-	output := C.malloc(C.uintptr_t(bytesLength))
-	defer C.free(unsafe.Pointer(output))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	C.ergo_lib_context_extension_keys(c.p, (*C.uint8_t)(output))
+	if c.p == nil {
+		panic(ErrClosed)
+	}
 
-	result := C.GoBytes(unsafe.Pointer(output), C.int(bytesLength))
+	result := c.keys()
 
 	return func(yield func(uint8) bool) {
 		for i := 0; i < len(result); i++ {
@@ -61,7 +66,31 @@ func (c *contextExtension) Keys() iter.Seq[uint8] {
 	}
 }
 
+// keys returns the keys of the ContextExtension, the caller holds the lock so the length
+// cannot change before the keys are written
+func (c *contextExtension) keys() []byte {
+	bytesLength := C.ergo_lib_context_extension_len(c.p)
+
+	output := C.malloc(C.uintptr_t(bytesLength))
+	defer C.free(unsafe.Pointer(output))
+
+	C.ergo_lib_context_extension_keys(c.p, (*C.uint8_t)(output))
+
+	return C.GoBytes(unsafe.Pointer(output), C.int(bytesLength))
+}
+
 func (c *contextExtension) Get(key uint8) (Constant, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.p == nil {
+		return nil, ErrClosed
+	}
+
+	return c.get(key)
+}
+
+func (c *contextExtension) get(key uint8) (Constant, error) {
 	var p C.ConstantPtr
 
 	res := C.ergo_lib_context_extension_get(c.p, C.uint8_t(key), &p)
@@ -79,17 +108,29 @@ func (c *contextExtension) Get(key uint8) (Constant, error) {
 }
 
 func (c *contextExtension) Set(key uint8, constant Constant) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_context_extension_set_pair(constant.pointer(), C.uint8_t(key), c.p)
 }
 
 func (c *contextExtension) All() iter.Seq2[uint8, Constant] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	return func(yield func(uint8, Constant) bool) {
-		for key := range c.Keys() {
-			ce, err := c.Get(key)
-			if err != nil {
-				return
-			}
-			if !yield(key, ce) {
+		keys, constants := c.entries()
+		for i, key := range keys {
+			if !yield(key, constants[i]) {
+				closeConstants(constants[i+1:])
 				return
 			}
 		}
@@ -97,23 +138,80 @@ func (c *contextExtension) All() iter.Seq2[uint8, Constant] {
 }
 
 func (c *contextExtension) Values() iter.Seq[Constant] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	return func(yield func(Constant) bool) {
-		for key := range c.Keys() {
-			ce, err := c.Get(key)
-			if err != nil {
-				return
-			}
-			if !yield(ce) {
+		_, constants := c.entries()
+		for i, constant := range constants {
+			if !yield(constant) {
+				closeConstants(constants[i+1:])
 				return
 			}
 		}
 	}
 }
 
+// entries returns the keys and constants of the ContextExtension read under a single lock. They are yielded
+// after the lock is released, so the caller may modify the ContextExtension while iterating
+func (c *contextExtension) entries() ([]uint8, []Constant) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.p == nil {
+		return nil, nil
+	}
+
+	var keys []uint8
+	var constants []Constant
+	for _, key := range c.keys() {
+		constant, err := c.get(key)
+		if err != nil {
+			break
+		}
+		keys = append(keys, key)
+		constants = append(constants, constant)
+	}
+	return keys, constants
+}
+
+func closeConstants(constants []Constant) {
+	for _, constant := range constants {
+		constant.Close()
+	}
+}
+
 func (c *contextExtension) pointer() C.ContextExtensionPtr {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	return c.p
 }
 
+func (c *contextExtension) rlock() {
+	c.mu.RLock()
+}
+
+func (c *contextExtension) runlock() {
+	c.mu.RUnlock()
+}
+
+func (c *contextExtension) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.p != nil {
+		runtime.SetFinalizer(c, nil)
+		finalizeContextExtension(c)
+		c.p = nil
+	}
+}
+
 func finalizeContextExtension(c *contextExtension) {
 	C.ergo_lib_context_extension_delete(c.p)
 }
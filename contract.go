@@ -15,6 +15,8 @@ type Contract interface {
 	Tree() Tree
 	// Equals checks if provided Contract is same
 	Equals(contract Contract) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ContractPtr
 }
 
@@ -69,6 +71,10 @@ func NewContractPayToAddress(payToAddress Address) (Contract, error) {
 }
 
 func (c *contract) Tree() Tree {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	var ergoTreePtr C.ErgoTreePtr
 	C.ergo_lib_contract_ergo_tree(c.p, &ergoTreePtr)
 
@@ -78,14 +84,30 @@ func (c *contract) Tree() Tree {
 }
 
 func (c *contract) Equals(contract Contract) bool {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_contract_eq(c.p, contract.pointer())
 	return bool(res)
 }
 
 func (c *contract) pointer() C.ContractPtr {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	return c.p
 }
 
+func (c *contract) Close() {
+	if c.p != nil {
+		runtime.SetFinalizer(c, nil)
+		finalizeContract(c)
+		c.p = nil
+	}
+}
+
 func finalizeContract(c *contract) {
 	C.ergo_lib_contract_delete(c.p)
 }
@@ -7,12 +7,15 @@ import "C"
 import (
 	"iter"
 	"runtime"
+	"sync"
 )
 
 // DataInput represent inputs that are used to enrich script context, but won't be spent by the transaction
 type DataInput interface {
 	// BoxId returns the BoxId of the DataInput
 	BoxId() BoxId
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.DataInputPtr
 }
 
@@ -34,6 +37,10 @@ func NewDataInput(boxId BoxId) DataInput {
 }
 
 func (d *dataInput) BoxId() BoxId {
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxIdPtr
 	C.ergo_lib_data_input_box_id(d.p, &p)
 	bi := &boxId{p: p}
@@ -41,9 +48,21 @@ func (d *dataInput) BoxId() BoxId {
 }
 
 func (d *dataInput) pointer() C.DataInputPtr {
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	return d.p
 }
 
+func (d *dataInput) Close() {
+	if d.p != nil {
+		runtime.SetFinalizer(d, nil)
+		finalizeDataInput(d)
+		d.p = nil
+	}
+}
+
 func finalizeDataInput(d *dataInput) {
 	C.ergo_lib_data_input_delete(d.p)
 }
@@ -58,11 +77,15 @@ type DataInputs interface {
 	Add(dataInput DataInput)
 	// All returns an iterator over all DataInput inside the collection
 	All() iter.Seq2[int, DataInput]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.DataInputsPtr
 }
 
 type dataInputs struct {
-	p C.DataInputsPtr
+	p  C.DataInputsPtr
+	mu sync.RWMutex
 }
 
 func newDataInputs(d *dataInputs) DataInputs {
@@ -79,11 +102,37 @@ func NewDataInputs() DataInputs {
 }
 
 func (d *dataInputs) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
+	return d.len()
+}
+
+func (d *dataInputs) len() int {
+	if d.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_data_inputs_len(d.p)
 	return int(res)
 }
 
 func (d *dataInputs) Get(index int) (DataInput, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.get(index)
+}
+
+func (d *dataInputs) get(index int) (DataInput, error) {
+	if d.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.DataInputPtr
 
 	res := C.ergo_lib_data_inputs_get(d.p, C.uintptr_t(index), &p)
@@ -101,27 +150,54 @@ func (d *dataInputs) Get(index int) (DataInput, error) {
 }
 
 func (d *dataInputs) Add(dataInput DataInput) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_data_inputs_add(dataInput.pointer(), d.p)
 }
 
 func (d *dataInputs) All() iter.Seq2[int, DataInput] {
-	return func(yield func(int, DataInput) bool) {
-		for i := 0; i < d.Len(); i++ {
-			tk, err := d.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(d, d.len, d.get)
 }
 
 func (d *dataInputs) pointer() C.DataInputsPtr {
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	return d.p
 }
 
+func (d *dataInputs) rlock() {
+	d.mu.RLock()
+}
+
+func (d *dataInputs) runlock() {
+	d.mu.RUnlock()
+}
+
+func (d *dataInputs) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.p != nil {
+		runtime.SetFinalizer(d, nil)
+		finalizeDataInputs(d)
+		d.p = nil
+	}
+}
+
 func finalizeDataInputs(d *dataInputs) {
 	C.ergo_lib_data_inputs_delete(d.p)
 }
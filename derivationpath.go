@@ -16,6 +16,8 @@ type DerivationPath interface {
 	Depth() uint32
 	// Next returns a new DerivationPath with the last element of the derivation path being increased, e.g. m/1/2 -> m/1/3
 	Next() (DerivationPath, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.DerivationPathPtr
 }
 
@@ -58,6 +60,10 @@ func NewDerivationPathFromString(s string) (DerivationPath, error) {
 }
 
 func (d *derivationPath) String() string {
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	var derivationPathStr *C.char
 
 	C.ergo_lib_derivation_path_to_str(d.p, &derivationPathStr)
@@ -67,10 +73,18 @@ func (d *derivationPath) String() string {
 }
 
 func (d *derivationPath) Depth() uint32 {
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	return uint32(C.ergo_lib_derivation_path_depth(d.p))
 }
 
 func (d *derivationPath) Next() (DerivationPath, error) {
+	if d.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.DerivationPathPtr
 
 	errPtr := C.ergo_lib_derivation_path_next(d.p, &p)
@@ -84,9 +98,21 @@ func (d *derivationPath) Next() (DerivationPath, error) {
 }
 
 func (d *derivationPath) pointer() C.DerivationPathPtr {
+	if d.p == nil {
+		panic(ErrClosed)
+	}
+
 	return d.p
 }
 
+func (d *derivationPath) Close() {
+	if d.p != nil {
+		runtime.SetFinalizer(d, nil)
+		finalizeDerivationPath(d)
+		d.p = nil
+	}
+}
+
 func finalizeDerivationPath(d *derivationPath) {
 	C.ergo_lib_derivation_path_delete(d.p)
 }
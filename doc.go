@@ -0,0 +1,18 @@
+// Package ergo is a Go wrapper around the C bindings of ErgoLib from sigma-rust.
+//
+// # Memory management
+//
+// Every value returned by this package holds memory allocated by the native library. It is released by a
+// finalizer once the value becomes unreachable, or immediately by calling Close. After Close a value must
+// not be used anymore, methods return ErrClosed or panic with it.
+//
+// # Concurrency
+//
+// Values that cannot change after creation, e.g. Box, Tree or Transaction, may be used from multiple
+// goroutines at once. Values with mutable native state, i.e. the collections, ContextExtension, HintsBag,
+// TransactionHintsBag, the builders, NipopowVerifier and Wallet, synchronise internally: their methods may be
+// called concurrently and they are read locked while they are passed as argument to a running operation, so
+// a Wallet can sign several transactions in parallel while the Boxes it reads from are appended to.
+// Iterating a collection with All observes concurrent additions made during the iteration.
+// Close must not be called while another goroutine is still using an immutable value.
+package ergo
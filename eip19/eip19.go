@@ -0,0 +1,168 @@
+// Package eip19 implements the QR code transport of the cold wallet protocol (EIP-19)
+// https://github.com/ergoplatform/eips/blob/master/eip-0019.md
+//
+// A ColdSigningRequest travels from the hot to the cold wallet as a sequence of CSR QR codes, the signed
+// transaction comes back as CSTX QR codes. Each QR code holds a JSON object with a part of the data and
+// its page: {"CSR":"<part>","n":<pages>,"p":<page>}. The data is itself JSON, {"reducedTx":...} for a
+// ColdSigningRequest and {"signedTx":"<base64>"} for a signed transaction. Boxes and transactions are passed
+// in their binary form, e.g. from ergo.Box.Bytes and ergo.Transaction.Bytes.
+package eip19
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// PrefixColdSigningRequest is the key of the data part in QR codes of a ColdSigningRequest
+	PrefixColdSigningRequest = "CSR"
+	// PrefixSignedTransaction is the key of the data part in QR codes of a signed transaction
+	PrefixSignedTransaction = "CSTX"
+)
+
+// ErrIncomplete is returned when data is read from a Reader that has not received all pages yet
+var ErrIncomplete = errors.New("eip19: not all pages received")
+
+// ColdSigningRequest is sent to the cold wallet to sign a transaction
+type ColdSigningRequest struct {
+	// ReducedTx is the serialized ReducedTransaction to sign
+	ReducedTx []byte
+	// Sender is the address of the wallet that is expected to sign
+	Sender string
+	// Inputs are the serialized boxes spent by the transaction, the cold wallet shows them to the user
+	Inputs [][]byte
+}
+
+type coldSigningRequestJson struct {
+	ReducedTx string   `json:"reducedTx"`
+	Sender    string   `json:"sender"`
+	Inputs    []string `json:"inputs,omitempty"`
+}
+
+func (c ColdSigningRequest) MarshalJSON() ([]byte, error) {
+	j := coldSigningRequestJson{
+		ReducedTx: base64.StdEncoding.EncodeToString(c.ReducedTx),
+		Sender:    c.Sender,
+	}
+	for _, input := range c.Inputs {
+		j.Inputs = append(j.Inputs, base64.StdEncoding.EncodeToString(input))
+	}
+	return json.Marshal(j)
+}
+
+func (c *ColdSigningRequest) UnmarshalJSON(data []byte) error {
+	var j coldSigningRequestJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	reducedTx, err := base64.StdEncoding.DecodeString(j.ReducedTx)
+	if err != nil {
+		return fmt.Errorf("eip19: decoding reducedTx: %w", err)
+	}
+	request := ColdSigningRequest{ReducedTx: reducedTx, Sender: j.Sender}
+	for i, input := range j.Inputs {
+		box, err := base64.StdEncoding.DecodeString(input)
+		if err != nil {
+			return fmt.Errorf("eip19: decoding input %d: %w", i, err)
+		}
+		request.Inputs = append(request.Inputs, box)
+	}
+
+	*c = request
+	return nil
+}
+
+// Chunks returns the QR code payloads for the ColdSigningRequest, none of them is longer than sizeLimit
+func (c ColdSigningRequest) Chunks(sizeLimit int) ([]string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return chunk(PrefixColdSigningRequest, string(data), sizeLimit)
+}
+
+type signedTransactionJson struct {
+	SignedTx string `json:"signedTx"`
+}
+
+// SignedTransactionChunks returns the QR code payloads for the serialized signed transaction,
+// none of them is longer than sizeLimit
+func SignedTransactionChunks(signedTx []byte, sizeLimit int) ([]string, error) {
+	data, err := json.Marshal(signedTransactionJson{SignedTx: base64.StdEncoding.EncodeToString(signedTx)})
+	if err != nil {
+		return nil, err
+	}
+	return chunk(PrefixSignedTransaction, string(data), sizeLimit)
+}
+
+// page is the JSON content of a single QR code, the data part is stored under the prefix as key
+type page struct {
+	data  string
+	pages int
+	index int
+}
+
+func (p page) marshal(prefix string) string {
+	data, _ := json.Marshal(p.data)
+	return `{"` + prefix + `":` + string(data) + `,"n":` + strconv.Itoa(p.pages) + `,"p":` + strconv.Itoa(p.index) + `}`
+}
+
+func chunk(prefix string, data string, sizeLimit int) ([]string, error) {
+	// the page numbers are part of every payload, so the number of pages grows until the parts fit
+	pages := 1
+	for {
+		budget := sizeLimit - len(page{pages: pages, index: pages}.marshal(prefix))
+		if budget < maxEscapedLen {
+			return nil, fmt.Errorf("eip19: size limit %d is too small", sizeLimit)
+		}
+
+		parts := split(data, budget)
+		if len(parts) > MaxPages {
+			return nil, fmt.Errorf("eip19: data needs more than %d pages of size %d", MaxPages, sizeLimit)
+		}
+		if len(parts) > pages {
+			pages = len(parts)
+			continue
+		}
+
+		result := make([]string, len(parts))
+		for i, part := range parts {
+			result[i] = page{data: part, pages: len(parts), index: i + 1}.marshal(prefix)
+		}
+		return result, nil
+	}
+}
+
+// split cuts data into parts whose JSON string encoding takes at most budget bytes without quotes
+func split(data string, budget int) []string {
+	var parts []string
+	start, size := 0, 0
+	for i := 0; i < len(data); i++ {
+		n := escapedLen(data[i])
+		if size+n > budget {
+			parts = append(parts, data[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	return append(parts, data[start:])
+}
+
+// maxEscapedLen is the longest JSON encoding of a single byte
+const maxEscapedLen = 6
+
+func escapedLen(c byte) int {
+	switch {
+	case c == '"' || c == '\\':
+		return 2
+	case c < 0x20 || c == '<' || c == '>' || c == '&':
+		// control characters and HTML characters are escaped as \u00XX by encoding/json
+		return maxEscapedLen
+	default:
+		return 1
+	}
+}
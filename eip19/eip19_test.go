@@ -0,0 +1,124 @@
+package eip19
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testRequest() ColdSigningRequest {
+	return ColdSigningRequest{
+		ReducedTx: bytes.Repeat([]byte{0xce, 0x01, 0x7f}, 300),
+		Sender:    "9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ",
+		Inputs:    [][]byte{bytes.Repeat([]byte{0x80}, 120), bytes.Repeat([]byte{0x01}, 60)},
+	}
+}
+
+func TestColdSigningRequest_Chunks(t *testing.T) {
+	request := testRequest()
+
+	chunks, err := request.Chunks(200)
+	assert.NoError(t, err)
+	assert.Greater(t, len(chunks), 1)
+	for _, c := range chunks {
+		assert.LessOrEqual(t, len(c), 200)
+	}
+
+	reader := NewColdSigningRequestReader()
+	// scanned in reverse order, the first one twice
+	for i := len(chunks) - 1; i >= 0; i-- {
+		assert.NoError(t, reader.Add(chunks[i]))
+	}
+	assert.NoError(t, reader.Add(chunks[len(chunks)-1]))
+	assert.True(t, reader.Complete())
+
+	decoded, err := reader.ColdSigningRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, request, decoded)
+}
+
+func TestColdSigningRequest_SingleChunk(t *testing.T) {
+	request := ColdSigningRequest{ReducedTx: []byte{1, 2, 3}, Sender: "addr"}
+
+	chunks, err := request.Chunks(4096)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"CSR":"{\"reducedTx\":\"AQID\",\"sender\":\"addr\"}","n":1,"p":1}`}, chunks)
+
+	_, err = request.Chunks(20)
+	assert.Error(t, err)
+	_, err = SignedTransactionChunks(bytes.Repeat([]byte{0xab}, 100000), 50)
+	assert.ErrorContains(t, err, "more than 1000 pages")
+}
+
+func TestReader_Missing(t *testing.T) {
+	chunks, err := SignedTransactionChunks(bytes.Repeat([]byte{0xab}, 500), 100)
+	assert.NoError(t, err)
+
+	reader := NewSignedTransactionReader()
+	assert.Nil(t, reader.Missing())
+	assert.False(t, reader.Complete())
+
+	assert.NoError(t, reader.Add(chunks[1]))
+	_, err = reader.SignedTransaction()
+	assert.ErrorIs(t, err, ErrIncomplete)
+	assert.Len(t, reader.Missing(), len(chunks)-1)
+	assert.NotContains(t, reader.Missing(), 2)
+
+	for _, c := range chunks {
+		assert.NoError(t, reader.Add(c))
+	}
+	signedTx, err := reader.SignedTransaction()
+	assert.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{0xab}, 500), signedTx)
+
+	_, err = reader.ColdSigningRequest()
+	assert.Error(t, err)
+}
+
+func TestReader_Add_Invalid(t *testing.T) {
+	reader := NewSignedTransactionReader()
+
+	assert.Error(t, reader.Add(`not json`))
+	assert.Error(t, reader.Add(`{"CSR":"AQID","n":1,"p":1}`))
+	assert.Error(t, reader.Add(`{"CSTX":"AQID","n":2,"p":3}`))
+	assert.ErrorContains(t, reader.Add(`{"CSTX":"AQID","n":2000000000,"p":1}`), "exceeds the maximum")
+	assert.Nil(t, reader.Missing())
+	assert.NoError(t, reader.Add(`{"CSTX":"AQID","n":1000,"p":1000}`))
+	reader.Reset()
+
+	assert.NoError(t, reader.Add(`{"CSTX":"AQID","n":2,"p":1}`))
+	assert.Error(t, reader.Add(`{"CSTX":"AQID","n":3,"p":2}`))
+	assert.Error(t, reader.Add(`{"CSTX":"AQIE","n":2,"p":1}`))
+
+	reader.Reset()
+	assert.NoError(t, reader.Add(`{"CSTX":"{\"signedTx\":\"AQID\"}"}`))
+	signedTx, err := reader.SignedTransaction()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, signedTx)
+
+	// the data is the JSON object, not the bare base64 of the transaction
+	reader.Reset()
+	assert.NoError(t, reader.Add(`{"CSTX":"AQID"}`))
+	_, err = reader.SignedTransaction()
+	assert.Error(t, err)
+}
+
+func TestSignedTransactionChunks(t *testing.T) {
+	chunks, err := SignedTransactionChunks([]byte{1, 2, 3}, 4096)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"CSTX":"{\"signedTx\":\"AQID\"}","n":1,"p":1}`}, chunks)
+}
+
+func TestReader_SignedTransaction_Pages(t *testing.T) {
+	// a wallet splits the JSON object at arbitrary positions, even inside the base64 of the transaction
+	pages := []string{
+		`{"CSTX":"{\"signedTx\":\"AQIDBAUG","n":2,"p":1}`,
+		`{"CSTX":"BwgJCg==\"}","n":2,"p":2}`,
+	}
+	reader := NewSignedTransactionReader()
+	assert.NoError(t, reader.Add(pages[1]))
+	assert.NoError(t, reader.Add(pages[0]))
+	signedTx, err := reader.SignedTransaction()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, signedTx)
+}
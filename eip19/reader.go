@@ -0,0 +1,147 @@
+package eip19
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// MaxPages is the largest page count a Reader accepts. The count is read from the scanned QR codes, the limit
+// keeps a single forged QR code from making the Reader allocate without bound. It is far above the few dozen
+// pages of a transaction with many inputs
+const MaxPages = 1000
+
+// Reader reassembles data from scanned QR code payloads. Pages can be added in any order,
+// scanning the same QR code twice is harmless
+type Reader struct {
+	prefix string
+	pages  []string
+	// received marks the pages that were added, indexed like pages
+	received []bool
+}
+
+// NewColdSigningRequestReader returns a Reader for the QR codes of a ColdSigningRequest
+func NewColdSigningRequestReader() *Reader {
+	return &Reader{prefix: PrefixColdSigningRequest}
+}
+
+// NewSignedTransactionReader returns a Reader for the QR codes of a signed transaction
+func NewSignedTransactionReader() *Reader {
+	return &Reader{prefix: PrefixSignedTransaction}
+}
+
+// Add adds the payload of a scanned QR code. It fails if the payload belongs to another kind of data
+// or does not match the pages added before
+func (r *Reader) Add(payload string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &fields); err != nil {
+		return fmt.Errorf("eip19: invalid payload: %w", err)
+	}
+
+	var p page
+	if err := json.Unmarshal(fields[r.prefix], &p.data); err != nil || fields[r.prefix] == nil {
+		return fmt.Errorf("eip19: payload has no %s data", r.prefix)
+	}
+	// a single QR code may omit the page numbers
+	p.pages, p.index = 1, 1
+	if n, ok := fields["n"]; ok {
+		if err := json.Unmarshal(n, &p.pages); err != nil {
+			return fmt.Errorf("eip19: invalid page count: %w", err)
+		}
+	}
+	if i, ok := fields["p"]; ok {
+		if err := json.Unmarshal(i, &p.index); err != nil {
+			return fmt.Errorf("eip19: invalid page number: %w", err)
+		}
+	}
+
+	if p.pages > MaxPages {
+		return fmt.Errorf("eip19: page count %d exceeds the maximum of %d", p.pages, MaxPages)
+	}
+	if p.pages < 1 || p.index < 1 || p.index > p.pages {
+		return fmt.Errorf("eip19: page %d of %d is out of range", p.index, p.pages)
+	}
+	if r.pages == nil {
+		r.pages = make([]string, p.pages)
+		r.received = make([]bool, p.pages)
+	} else if len(r.pages) != p.pages {
+		return fmt.Errorf("eip19: page count %d differs from %d of previous pages", p.pages, len(r.pages))
+	}
+
+	i := p.index - 1
+	if r.received[i] && r.pages[i] != p.data {
+		return fmt.Errorf("eip19: page %d was received with different content", p.index)
+	}
+	r.pages[i] = p.data
+	r.received[i] = true
+	return nil
+}
+
+// Complete reports whether all pages have been added
+func (r *Reader) Complete() bool {
+	return r.pages != nil && !slices.Contains(r.received, false)
+}
+
+// Missing returns the numbers of the pages that are still missing, starting at 1.
+// It returns nil before the first page has been added, as the number of pages is unknown until then
+func (r *Reader) Missing() []int {
+	var missing []int
+	for i, ok := range r.received {
+		if !ok {
+			missing = append(missing, i+1)
+		}
+	}
+	return missing
+}
+
+// Reset discards all added pages
+func (r *Reader) Reset() {
+	r.pages, r.received = nil, nil
+}
+
+func (r *Reader) data() (string, error) {
+	if !r.Complete() {
+		return "", ErrIncomplete
+	}
+	return strings.Join(r.pages, ""), nil
+}
+
+// ColdSigningRequest returns the reassembled ColdSigningRequest
+func (r *Reader) ColdSigningRequest() (ColdSigningRequest, error) {
+	if r.prefix != PrefixColdSigningRequest {
+		return ColdSigningRequest{}, fmt.Errorf("eip19: reader holds %s data", r.prefix)
+	}
+	data, err := r.data()
+	if err != nil {
+		return ColdSigningRequest{}, err
+	}
+
+	var request ColdSigningRequest
+	if err := json.Unmarshal([]byte(data), &request); err != nil {
+		return ColdSigningRequest{}, err
+	}
+	return request, nil
+}
+
+// SignedTransaction returns the reassembled serialized signed transaction
+func (r *Reader) SignedTransaction() ([]byte, error) {
+	if r.prefix != PrefixSignedTransaction {
+		return nil, fmt.Errorf("eip19: reader holds %s data", r.prefix)
+	}
+	data, err := r.data()
+	if err != nil {
+		return nil, err
+	}
+
+	var j signedTransactionJson
+	if err := json.Unmarshal([]byte(data), &j); err != nil {
+		return nil, err
+	}
+	signedTx, err := base64.StdEncoding.DecodeString(j.SignedTx)
+	if err != nil {
+		return nil, fmt.Errorf("eip19: decoding signedTx: %w", err)
+	}
+	return signedTx, nil
+}
@@ -0,0 +1,142 @@
+package ergo
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Null holds a value decoded from JSON, text or a database column. Values of this package are backed by
+// native memory and can only be created through their constructors, so they cannot be used directly as
+// destination for json.Unmarshal or sql.Scanner. Null fills this gap for BoxId, TokenId, TxId, BoxValue,
+// Tree, Box and Transaction. Valid is false when the decoded value was JSON null or SQL NULL
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNull wraps v into a valid Null
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.V)
+}
+
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Null[T]{}
+		return nil
+	}
+
+	switch any(&n.V).(type) {
+	case *Box, *Transaction:
+		return n.decode(string(data))
+	case *BoxValue:
+		var v int64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		return n.decode(strconv.FormatInt(v, 10))
+	default:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return n.decode(s)
+	}
+}
+
+func (n Null[T]) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	switch v := any(n.V).(type) {
+	case interface{ MarshalText() ([]byte, error) }:
+		return v.MarshalText()
+	default:
+		return json.Marshal(n.V)
+	}
+}
+
+func (n *Null[T]) UnmarshalText(text []byte) error {
+	return n.decode(string(text))
+}
+
+// Scan implements sql.Scanner. Text and byte columns are decoded like UnmarshalText,
+// integer columns are accepted for BoxValue
+func (n *Null[T]) Scan(src any) error {
+	switch s := src.(type) {
+	case nil:
+		*n = Null[T]{}
+		return nil
+	case string:
+		return n.decode(s)
+	case []byte:
+		return n.decode(string(s))
+	case int64:
+		return n.decode(strconv.FormatInt(s, 10))
+	default:
+		return fmt.Errorf("ergo: cannot scan %T into %T", src, n.V)
+	}
+}
+
+// Value implements driver.Valuer
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	switch v := any(n.V).(type) {
+	case driver.Valuer:
+		return v.Value()
+	default:
+		b, err := json.Marshal(n.V)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+}
+
+// decode sets n to the value decoded from s, n is left unchanged if s cannot be decoded
+func (n *Null[T]) decode(s string) error {
+	var value T
+	var err error
+	switch v := any(&value).(type) {
+	case *BoxId:
+		*v, err = NewBoxId(s)
+	case *TokenId:
+		*v, err = NewTokenId(s)
+	case *TxId:
+		*v, err = NewTxId(s)
+	case *Tree:
+		*v, err = NewTree(s)
+	case *Box:
+		*v, err = NewBoxFromJson(s)
+	case *Transaction:
+		*v, err = NewTransactionFromJson(s)
+	case *BoxValue:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, 64)
+		if err == nil {
+			*v, err = NewBoxValue(i)
+		}
+	default:
+		return fmt.Errorf("ergo: decoding %T is not supported", n.V)
+	}
+	if err != nil {
+		return err
+	}
+
+	*n = Null[T]{V: value, Valid: true}
+	return nil
+}
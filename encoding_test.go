@@ -0,0 +1,55 @@
+package ergo
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNull_JSON(t *testing.T) {
+	type record struct {
+		BoxId   Null[BoxId]    `json:"boxId"`
+		Value   Null[BoxValue] `json:"value"`
+		TokenId Null[TokenId]  `json:"tokenId"`
+	}
+
+	data := `{"boxId":"8452e43011f522a3432a04e4aa77e293fc8c3817a11a2088da49201b88158f8a","value":1000000,"tokenId":null}`
+
+	var r record
+	err := json.Unmarshal([]byte(data), &r)
+	assert.NoError(t, err)
+
+	assert.True(t, r.BoxId.Valid)
+	assert.Equal(t, "8452e43011f522a3432a04e4aa77e293fc8c3817a11a2088da49201b88158f8a", r.BoxId.V.Base16())
+	assert.Equal(t, int64(1000000), r.Value.V.Int64())
+	assert.False(t, r.TokenId.Valid)
+
+	encoded, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.JSONEq(t, data, string(encoded))
+}
+
+func TestNull_Scan(t *testing.T) {
+	var value Null[BoxValue]
+	assert.NoError(t, value.Scan(int64(1000000)))
+	assert.Equal(t, int64(1000000), value.V.Int64())
+
+	var tree Null[Tree]
+	assert.NoError(t, tree.Scan([]byte("0008cd0327e65711a59378c59359c3e1d0f7abe906479eccb76094e50fe79d743ccc15e6")))
+	v, err := tree.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "0008cd0327e65711a59378c59359c3e1d0f7abe906479eccb76094e50fe79d743ccc15e6", v)
+
+	assert.NoError(t, tree.Scan(nil))
+	assert.False(t, tree.Valid)
+
+	var boxId Null[BoxId]
+	assert.Error(t, boxId.Scan("invalid"))
+	assert.False(t, boxId.Valid)
+
+	// a failed decode keeps the value decoded before
+	assert.NoError(t, boxId.Scan("8452e43011f522a3432a04e4aa77e293fc8c3817a11a2088da49201b88158f8a"))
+	assert.Error(t, boxId.Scan("invalid"))
+	assert.True(t, boxId.Valid)
+	assert.Equal(t, "8452e43011f522a3432a04e4aa77e293fc8c3817a11a2088da49201b88158f8a", boxId.V.Base16())
+}
@@ -0,0 +1,159 @@
+// Package ergopay implements the dApp side of ErgoPay (EIP-20), a protocol for signing transactions
+// with a mobile wallet https://github.com/ergoplatform/eips/blob/master/eip-0020.md
+//
+// The transaction to sign is transferred as serialized ReducedTransaction. ergo-lib-c does not expose
+// that serialization, so this library cannot produce the bytes from an ergo.ReducedTransaction. They have
+// to be created by a component that reduces the transaction itself, e.g. with sigma_serialize_bytes of
+// ReducedTransaction in sigma-rust or its JavaScript bindings ergo-lib-wasm, or with ReducedTransaction.toBytes
+// of ergo-appkit, and are passed through by this package unchanged.
+package ergopay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Scheme is the URI scheme of ErgoPay links
+const Scheme = "ergopay"
+
+// AddressPlaceholder can be used in dynamic request URLs, the wallet replaces it with the address
+// of the wallet before it sends the request
+const AddressPlaceholder = "#P2PK_ADDRESS#"
+
+// Severity of the message shown by the wallet
+type Severity string
+
+const (
+	// SeverityNone shows the message without emphasis
+	SeverityNone Severity = "NONE"
+	// SeverityInformation shows the message as information
+	SeverityInformation Severity = "INFORMATION"
+	// SeverityWarning shows the message as warning
+	SeverityWarning Severity = "WARNING"
+	// SeverityError shows the message as error, the wallet does not offer to sign the transaction
+	SeverityError Severity = "ERROR"
+)
+
+// SigningRequest is the ErgoPaySigningRequest returned to the wallet for a dynamic request
+type SigningRequest struct {
+	// ReducedTx is the serialized ReducedTransaction to sign, created outside of this library as described in the
+	// package documentation. It may be empty for requests that only carry a message
+	ReducedTx []byte
+	// Address is the address the wallet should use for signing, the wallet lets the user choose if empty
+	Address string
+	// Message is shown to the user by the wallet
+	Message string
+	// MessageSeverity determines how the wallet shows Message
+	MessageSeverity Severity
+	// ReplyTo is the URL the wallet posts a Reply to after it submitted the transaction
+	ReplyTo string
+}
+
+type signingRequestJson struct {
+	ReducedTx       string   `json:"reducedTx,omitempty"`
+	Address         string   `json:"address,omitempty"`
+	Message         string   `json:"message,omitempty"`
+	MessageSeverity Severity `json:"messageSeverity,omitempty"`
+	ReplyTo         string   `json:"replyTo,omitempty"`
+}
+
+// ErrorRequest creates a SigningRequest without transaction that shows message as error in the wallet
+func ErrorRequest(message string) *SigningRequest {
+	return &SigningRequest{Message: message, MessageSeverity: SeverityError}
+}
+
+func (s SigningRequest) MarshalJSON() ([]byte, error) {
+	j := signingRequestJson{
+		Address:         s.Address,
+		Message:         s.Message,
+		MessageSeverity: s.MessageSeverity,
+		ReplyTo:         s.ReplyTo,
+	}
+	if len(s.ReducedTx) > 0 {
+		j.ReducedTx = base64.RawURLEncoding.EncodeToString(s.ReducedTx)
+	}
+	return json.Marshal(j)
+}
+
+func (s *SigningRequest) UnmarshalJSON(data []byte) error {
+	var j signingRequestJson
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	reducedTx, err := decodeBase64URL(j.ReducedTx)
+	if err != nil {
+		return err
+	}
+
+	*s = SigningRequest{
+		ReducedTx:       reducedTx,
+		Address:         j.Address,
+		Message:         j.Message,
+		MessageSeverity: j.MessageSeverity,
+		ReplyTo:         j.ReplyTo,
+	}
+	return nil
+}
+
+// Reply is sent by the wallet to SigningRequest.ReplyTo after it submitted the signed transaction
+type Reply struct {
+	TxId string `json:"txId"`
+}
+
+// StaticURL returns an ErgoPay link that contains the serialized ReducedTransaction itself, see the package
+// documentation for where reducedTx comes from.
+// Static links are limited by the size a QR code can hold, prefer DynamicURL for larger transactions
+func StaticURL(reducedTx []byte) string {
+	return Scheme + ":" + base64.RawURLEncoding.EncodeToString(reducedTx)
+}
+
+// DynamicURL returns an ErgoPay link for requestURL, the wallet fetches the SigningRequest from there.
+// requestURL must be a https URL and may contain AddressPlaceholder
+func DynamicURL(requestURL string) (string, error) {
+	// AddressPlaceholder would start the fragment, it is swapped with a token the URL does not contain
+	token := "p2pk-address"
+	for strings.Contains(requestURL, token) {
+		token += "-"
+	}
+	u, err := url.Parse(strings.ReplaceAll(requestURL, AddressPlaceholder, token))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return "", errors.New("ergopay: request URL must be an absolute https URL")
+	}
+
+	u.Scheme = ""
+	return Scheme + ":" + strings.ReplaceAll(u.String(), token, AddressPlaceholder), nil
+}
+
+// ParseURL parses an ErgoPay link. For static links the serialized ReducedTransaction is returned,
+// for dynamic links the https URL to fetch the SigningRequest from
+func ParseURL(link string) (reducedTx []byte, requestURL string, err error) {
+	rest, ok := strings.CutPrefix(link, Scheme+":")
+	if !ok {
+		return nil, "", errors.New("ergopay: not an ergopay link")
+	}
+
+	if dynamic, ok := strings.CutPrefix(rest, "//"); ok {
+		return nil, "https://" + dynamic, nil
+	}
+
+	reducedTx, err = decodeBase64URL(rest)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(reducedTx) == 0 {
+		return nil, "", errors.New("ergopay: empty static link")
+	}
+	return reducedTx, "", nil
+}
+
+// decodeBase64URL decodes url-safe base64 with or without padding, as wallets accept both
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
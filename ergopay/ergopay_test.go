@@ -0,0 +1,63 @@
+package ergopay
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSigningRequest_JSON(t *testing.T) {
+	request := SigningRequest{
+		ReducedTx:       []byte{0xfb, 0xff, 0x01},
+		Address:         "9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ",
+		Message:         "Pay for order 42",
+		MessageSeverity: SeverityInformation,
+	}
+
+	encoded, err := json.Marshal(request)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"reducedTx":"-_8B","address":"9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ","message":"Pay for order 42","messageSeverity":"INFORMATION"}`, string(encoded))
+
+	var decoded SigningRequest
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, request, decoded)
+
+	encoded, _ = json.Marshal(ErrorRequest("insufficient funds"))
+	assert.JSONEq(t, `{"message":"insufficient funds","messageSeverity":"ERROR"}`, string(encoded))
+}
+
+func TestStaticURL(t *testing.T) {
+	link := StaticURL([]byte{0xfb, 0xff, 0x01})
+	assert.Equal(t, "ergopay:-_8B", link)
+
+	reducedTx, requestURL, err := ParseURL(link)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xfb, 0xff, 0x01}, reducedTx)
+	assert.Empty(t, requestURL)
+
+	_, _, err = ParseURL("ergopay:")
+	assert.Error(t, err)
+	_, _, err = ParseURL("https://example.com")
+	assert.Error(t, err)
+}
+
+func TestDynamicURL(t *testing.T) {
+	link, err := DynamicURL("https://dapp.example.com/pay/42/" + AddressPlaceholder)
+	assert.NoError(t, err)
+	assert.Equal(t, "ergopay://dapp.example.com/pay/42/#P2PK_ADDRESS#", link)
+
+	reducedTx, requestURL, err := ParseURL(link)
+	assert.NoError(t, err)
+	assert.Nil(t, reducedTx)
+	assert.Equal(t, "https://dapp.example.com/pay/42/#P2PK_ADDRESS#", requestURL)
+
+	// the scheme is case insensitive
+	link, err = DynamicURL("HTTPS://dapp.example.com/pay?id=p2pk-address&address=" + AddressPlaceholder)
+	assert.NoError(t, err)
+	assert.Equal(t, "ergopay://dapp.example.com/pay?id=p2pk-address&address=#P2PK_ADDRESS#", link)
+
+	_, err = DynamicURL("http://dapp.example.com/pay")
+	assert.Error(t, err)
+	_, err = DynamicURL("/pay")
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package ergopay
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// maxReplySize limits the body of a Reply, a TxId reply is well below this
+const maxReplySize = 1 << 12
+
+// Handler serves dynamic ErgoPay requests. GET requests are answered with the SigningRequest returned
+// by Request, POST requests are decoded as Reply and passed to Reply. Mount it at the URL passed to DynamicURL
+// and, if replies are wanted, set SigningRequest.ReplyTo to the same URL
+type Handler struct {
+	// Request creates the SigningRequest for r. The wallet address is part of r if the request URL
+	// contained AddressPlaceholder. Errors are not shown to the user, return ErrorRequest for that.
+	// Returning neither a SigningRequest nor an error is answered like an error
+	Request func(r *http.Request) (*SigningRequest, error)
+	// Reply is called with the TxId reported by the wallet, POST requests are rejected if it is nil
+	Reply func(r *http.Request, reply Reply) error
+	// ErrorLog logs errors returned by Request and Reply, the standard logger is used if nil
+	ErrorLog *log.Logger
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && h.Request != nil:
+		h.serveRequest(w, r)
+	case r.Method == http.MethodPost && h.Reply != nil:
+		h.serveReply(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) serveRequest(w http.ResponseWriter, r *http.Request) {
+	request, err := h.Request(r)
+	if err != nil {
+		h.logf("ergopay: creating signing request: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if request == nil {
+		h.logf("ergopay: creating signing request: Request returned no signing request")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		h.logf("ergopay: encoding signing request: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func (h *Handler) serveReply(w http.ResponseWriter, r *http.Request) {
+	var reply Reply
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReplySize)).Decode(&reply); err != nil || reply.TxId == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Reply(r, reply); err != nil {
+		h.logf("ergopay: handling reply: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) logf(format string, args ...any) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
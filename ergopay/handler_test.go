@@ -0,0 +1,86 @@
+package ergopay
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testServer(t *testing.T, replies chan<- Reply) *httptest.Server {
+	mux := http.NewServeMux()
+	handler := &Handler{
+		Request: func(r *http.Request) (*SigningRequest, error) {
+			address := r.PathValue("address")
+			if address == "broken" {
+				return nil, errors.New("node unavailable")
+			}
+			if address == "missing" {
+				return nil, nil
+			}
+			return &SigningRequest{ReducedTx: []byte{1, 2, 3}, Address: address, ReplyTo: "https://dapp.example.com/pay/" + address}, nil
+		},
+		Reply: func(r *http.Request, reply Reply) error {
+			replies <- reply
+			return nil
+		},
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	mux.Handle("/pay/{address}", handler)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandler_Request(t *testing.T) {
+	server := testServer(t, nil)
+
+	resp, err := http.Get(server.URL + "/pay/9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"reducedTx":"AQID","address":"9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ","replyTo":"https://dapp.example.com/pay/9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ"}`, string(body))
+
+	resp, err = http.Get(server.URL + "/pay/broken")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	// no signing request is not answered with null
+	resp, err = http.Get(server.URL + "/pay/missing")
+	assert.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.NotContains(t, string(body), "null")
+}
+
+func TestHandler_Reply(t *testing.T) {
+	replies := make(chan Reply, 1)
+	server := testServer(t, replies)
+
+	resp, err := http.Post(server.URL+"/pay/addr", "application/json", strings.NewReader(`{"txId":"93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38"}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38", (<-replies).TxId)
+
+	resp, err = http.Post(server.URL+"/pay/addr", "application/json", strings.NewReader(`{}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/pay/addr", nil)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
@@ -13,6 +13,11 @@ import (
 // if there is no error contained in the error pointer.
 const nilErrorStr = "success"
 
+// ErrClosed is returned when a method is called on a value after its Close method has been called.
+// Methods that do not return an error panic with ErrClosed instead, the same applies to closed values
+// that are passed as arguments.
+var ErrClosed = errors.New("ergo: use of closed value")
+
 type ergoError struct {
 	p C.ErrorPtr
 }
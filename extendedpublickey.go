@@ -5,8 +5,12 @@ package ergo
 */
 import "C"
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"runtime"
+	"slices"
 	"unsafe"
 )
 
@@ -17,11 +21,23 @@ type ExtendedPublicKey interface {
 	Derive(derivationPath DerivationPath) (ExtendedPublicKey, error)
 	// Address returns the Address associated with the ExtendedPublicKey
 	Address() Address
+	// Base58 returns the ExtendedPublicKey serialized as defined by BIP-32, an xpub string for MainnetPrefix and
+	// a tpub string for TestnetPrefix. The parent fingerprint is 0 for keys created by NewExtendedPublicKey.
+	// It fails if the derivation path of the key could not be parsed
+	Base58(prefix NetworkPrefix) (string, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ExtPubKeyPtr
 }
 
 type extendedPublicKey struct {
 	p C.ExtPubKeyPtr
+	// chainCode, path and parentFingerprint are tracked on the Go side as ergo-lib-c does not expose them
+	chainCode         []byte
+	path              []uint32
+	parentFingerprint uint32
+	// pathErr is set if the path of the key could not be determined, the key can not be serialized then
+	pathErr error
 }
 
 func newExtendedPublicKey(e *extendedPublicKey) ExtendedPublicKey {
@@ -34,13 +50,18 @@ func newExtendedPublicKey(e *extendedPublicKey) ExtendedPublicKey {
 // chainCode needs to be the length of 32 bytes
 func NewExtendedPublicKey(publicKeyBytes []byte, chainCode []byte, derivationPath DerivationPath) (ExtendedPublicKey, error) {
 	if len(publicKeyBytes) != 33 {
-		return nil, errors.New("secretKeyBytes must be 32 bytes")
+		return nil, errors.New("publicKeyBytes must be 33 bytes")
 	}
 
 	if len(chainCode) != 32 {
 		return nil, errors.New("chainCode must be 32 bytes")
 	}
 
+	path, pathErr := parseDerivationPath(derivationPath.String())
+	if pathErr != nil {
+		return nil, pathErr
+	}
+
 	publicKeyByteData := C.CBytes(publicKeyBytes)
 	defer C.free(unsafe.Pointer(publicKeyByteData))
 	chainCodeByteData := C.CBytes(chainCode)
@@ -53,41 +74,183 @@ func NewExtendedPublicKey(publicKeyBytes []byte, chainCode []byte, derivationPat
 		return nil, err.error()
 	}
 
-	e := &extendedPublicKey{p: p}
+	e := &extendedPublicKey{p: p, chainCode: bytes.Clone(chainCode), path: path}
 	return newExtendedPublicKey(e), nil
 }
 
+// NewExtendedPublicKeyFromBase58 parses an ExtendedPublicKey serialized as defined by BIP-32, see
+// ExtendedPublicKey.Base58. The serialization does not contain the full derivation path, so only keys of EIP-3
+// accounts are supported, i.e. keys at depth 3 with the path m/44'/429'/account'. Keys at any other depth are
+// rejected
+func NewExtendedPublicKeyFromBase58(s string) (ExtendedPublicKey, error) {
+	data, err := base58CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != extendedKeyLength {
+		return nil, fmt.Errorf("extended public key must be %d bytes", extendedKeyLength)
+	}
+	if version := binary.BigEndian.Uint32(data); version != xpubVersion && version != tpubVersion {
+		return nil, fmt.Errorf("unknown extended public key version %#x", version)
+	}
+	depth := data[4]
+	parentFingerprint := binary.BigEndian.Uint32(data[5:])
+	childNumber := binary.BigEndian.Uint32(data[9:])
+	chainCode := data[13 : 13+chainCodeLength]
+	publicKey := data[13+chainCodeLength:]
+	if depth != 3 || childNumber < hardenedIndex {
+		return nil, fmt.Errorf("extended public key at depth %d is not the key of an EIP-3 account, only keys at depth 3 (m/44'/429'/account') are supported", depth)
+	}
+
+	derivationPath, err := NewDerivationPathFromString(formatDerivationPath([]uint32{44 + hardenedIndex, 429 + hardenedIndex, childNumber}))
+	if err != nil {
+		return nil, err
+	}
+	defer derivationPath.Close()
+	key, err := NewExtendedPublicKey(publicKey, chainCode, derivationPath)
+	if err != nil {
+		return nil, err
+	}
+	key.(*extendedPublicKey).parentFingerprint = parentFingerprint
+	return key, nil
+}
+
 func (e *extendedPublicKey) Child(childIndex uint32) (ExtendedPublicKey, error) {
+	if e.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.ExtPubKeyPtr
 	errPtr := C.ergo_lib_ext_pub_key_child(e.p, C.uint32_t(childIndex), &p)
-	err := newError(errPtr)
-	if err.isError() {
-		return nil, err.error()
+	cErr := newError(errPtr)
+	if cErr.isError() {
+		return nil, cErr.error()
 	}
-	return newExtendedPublicKey(e), nil
+	ep := &extendedPublicKey{p: p}
+	child := newExtendedPublicKey(ep)
+	if err := e.childMetadata(ep, childIndex); err != nil {
+		child.Close()
+		return nil, err
+	}
+	return child, nil
 }
 
+// Derive walks derivationPath from e with Child, so every level is derived once by ergo-lib-c and its chain
+// code is computed in Go along the way
 func (e *extendedPublicKey) Derive(derivationPath DerivationPath) (ExtendedPublicKey, error) {
+	if e.p == nil {
+		return nil, ErrClosed
+	}
+	if e.pathErr != nil {
+		return nil, e.pathErr
+	}
+	path, err := parseDerivationPath(derivationPath.String())
+	if err != nil {
+		return nil, err
+	}
+	indices, err := childPath(e.path, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return e.copy(derivationPath)
+	}
+
+	var key ExtendedPublicKey = e
+	for _, index := range indices {
+		child, err := key.Child(index)
+		if key != ExtendedPublicKey(e) {
+			key.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+// copy returns a new ExtendedPublicKey for e, derivationPath is the path of e
+func (e *extendedPublicKey) copy(derivationPath DerivationPath) (ExtendedPublicKey, error) {
 	var p C.ExtPubKeyPtr
 	errPtr := C.ergo_lib_ext_pub_key_derive(e.p, derivationPath.pointer(), &p)
 	err := newError(errPtr)
 	if err.isError() {
 		return nil, err.error()
 	}
-	return newExtendedPublicKey(e), nil
+	ep := &extendedPublicKey{p: p, chainCode: bytes.Clone(e.chainCode), path: slices.Clone(e.path), parentFingerprint: e.parentFingerprint}
+	return newExtendedPublicKey(ep), nil
+}
+
+// childMetadata sets the chain code, path and parent fingerprint of child, the child at index of e
+func (e *extendedPublicKey) childMetadata(child *extendedPublicKey, index uint32) error {
+	if e.pathErr != nil {
+		return e.pathErr
+	}
+	publicKey, err := e.publicKey()
+	if err != nil {
+		return err
+	}
+	child.chainCode = childChainCode(e.chainCode, publicKey, index)
+	child.path = append(e.path[:len(e.path):len(e.path)], index)
+	child.parentFingerprint = fingerprint(publicKey)
+	return nil
+}
+
+// publicKey returns the compressed public key of e, taken from the tree of its P2PK address
+func (e *extendedPublicKey) publicKey() ([]byte, error) {
+	a := e.Address()
+	defer a.Close()
+	proposition, err := p2pkProposition(a)
+	if err != nil {
+		return nil, err
+	}
+	return proposition[1:], nil
 }
 
 func (e *extendedPublicKey) Address() Address {
+	if e.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.AddressPtr
 	C.ergo_lib_ext_pub_key_address(e.p, &p)
 	a := &address{p: p}
 	return newAddress(a)
 }
 
+func (e *extendedPublicKey) Base58(prefix NetworkPrefix) (string, error) {
+	if e.pathErr != nil {
+		return "", e.pathErr
+	}
+	publicKey, err := e.publicKey()
+	if err != nil {
+		return "", err
+	}
+
+	version := uint32(xpubVersion)
+	if prefix == TestnetPrefix {
+		version = tpubVersion
+	}
+	return encodeExtendedPublicKey(version, e.path, e.parentFingerprint, e.chainCode, publicKey), nil
+}
+
 func (e *extendedPublicKey) pointer() C.ExtPubKeyPtr {
+	if e.p == nil {
+		panic(ErrClosed)
+	}
+
 	return e.p
 }
 
+func (e *extendedPublicKey) Close() {
+	if e.p != nil {
+		runtime.SetFinalizer(e, nil)
+		finalizeExtendedPublicKey(e)
+		e.p = nil
+	}
+}
+
 func finalizeExtendedPublicKey(e *extendedPublicKey) {
 	C.ergo_lib_ext_pub_key_delete(e.p)
 }
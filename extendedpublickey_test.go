@@ -16,3 +16,95 @@ func TestNewExtendedPublicKey(t *testing.T) {
 	assert.NoError(t, extPubKeyErr)
 	assert.Equal(t, "9gHMTduN2xseqb5NMKQtNSeS7Pe6wm7AwGoLoMERidWDERQunvn", extPubKey.Address().Base58(MainnetPrefix))
 }
+
+func TestExtendedPublicKey_Base58(t *testing.T) {
+	// test vector 2 of BIP-32
+	seed, _ := hex.DecodeString("fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542")
+	master, err := DeriveMaster(seed)
+	assert.NoError(t, err)
+	encoded, err := master.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB", encoded)
+
+	child, err := master.Child("0")
+	assert.NoError(t, err)
+	childEncoded, err := child.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH", childEncoded)
+
+	publicChild, err := master.ExtendedPublicKey().Child(0)
+	assert.NoError(t, err)
+	publicChildEncoded, err := publicChild.Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, childEncoded, publicChildEncoded)
+}
+
+func TestExtendedSecretKey_DeriveFromMaster(t *testing.T) {
+	seed := MnemonicToSeed("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "")
+	master, err := DeriveMaster(seed)
+	assert.NoError(t, err)
+	path, _ := NewDerivationPathFromString("m/44'/429'/0'/0/0")
+	key, err := master.Derive(path)
+	assert.NoError(t, err)
+
+	publicKey := key.ExtendedPublicKey()
+	assert.Equal(t, "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r", publicKey.Address().Base58(MainnetPrefix))
+	encoded, err := publicKey.Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, "xpub", encoded[:4])
+
+	// deriving the public key of the account gives the same key
+	accountPath, _ := NewDerivationPathFromString("m/44'/429'/0'")
+	accountKey, err := master.Derive(accountPath)
+	assert.NoError(t, err)
+	publicKeyFromAccount, err := accountKey.ExtendedPublicKey().Derive(path)
+	assert.NoError(t, err)
+	encodedFromAccount, err := publicKeyFromAccount.Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, encodedFromAccount)
+
+	// deriving the path of the key itself gives a copy
+	accountCopy, err := accountKey.Derive(accountPath)
+	assert.NoError(t, err)
+	accountEncoded, err := accountKey.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	accountCopyEncoded, err := accountCopy.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, accountEncoded, accountCopyEncoded)
+	otherAccountPath, _ := NewDerivationPathFromString("m/44'/429'/1'")
+	_, err = accountKey.Derive(otherAccountPath)
+	assert.ErrorContains(t, err, "derivation path does not extend the path of the key")
+}
+
+func TestNewExtendedPublicKeyFromBase58(t *testing.T) {
+	seed := MnemonicToSeed("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "")
+	master, _ := DeriveMaster(seed)
+	accountPath, _ := NewDerivationPathFromString("m/44'/429'/0'")
+	accountKey, err := master.Derive(accountPath)
+	assert.NoError(t, err)
+
+	encoded, err := accountKey.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	decoded, err := NewExtendedPublicKeyFromBase58(encoded)
+	assert.NoError(t, err)
+	decodedEncoded, err := decoded.Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded, decodedEncoded)
+
+	addressKey, err := decoded.Derive(mustDerivationPath(t, 0, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r", addressKey.Address().Base58(MainnetPrefix))
+
+	masterEncoded, err := master.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	_, err = NewExtendedPublicKeyFromBase58(masterEncoded)
+	assert.ErrorContains(t, err, "extended public key at depth 0 is not the key of an EIP-3 account")
+	_, err = NewExtendedPublicKeyFromBase58(encoded[:len(encoded)-1] + "1")
+	assert.Error(t, err)
+}
+
+func mustDerivationPath(t *testing.T, account uint32, index uint32) DerivationPath {
+	path, err := NewDerivationPath(account, []uint32{index})
+	assert.NoError(t, err)
+	return path
+}
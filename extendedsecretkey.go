@@ -5,6 +5,7 @@ package ergo
 */
 import "C"
 import (
+	"bytes"
 	"errors"
 	"runtime"
 	"unsafe"
@@ -23,10 +24,18 @@ type ExtendedSecretKey interface {
 	ExtendedPublicKey() ExtendedPublicKey
 	// Derive derives a new ExtendedSecretKey from the supplied DerivationPath
 	Derive(derivationPath DerivationPath) (ExtendedSecretKey, error)
+	// Destroy wipes the chain code kept in Go memory and closes the ExtendedSecretKey. The native secret key
+	// is freed but not zeroed, ergo-lib-c offers no way to wipe it
+	Destroy()
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type extendedSecretKey struct {
 	p C.ExtSecretKeyPtr
+	// chainCode and parentFingerprint are tracked on the Go side as ergo-lib-c does not expose them
+	chainCode         []byte
+	parentFingerprint uint32
 }
 
 func newExtendedSecretKey(e *extendedSecretKey) ExtendedSecretKey {
@@ -47,9 +56,9 @@ func NewExtendedSecretKey(secretKeyBytes []byte, chainCode []byte, derivationPat
 	}
 
 	secretKeyByteData := C.CBytes(secretKeyBytes)
-	defer C.free(unsafe.Pointer(secretKeyByteData))
+	defer freeSecret(secretKeyByteData, len(secretKeyBytes))
 	chainCodeByteData := C.CBytes(chainCode)
-	defer C.free(unsafe.Pointer(chainCodeByteData))
+	defer freeSecret(chainCodeByteData, len(chainCode))
 
 	var p C.ExtSecretKeyPtr
 	errPtr := C.ergo_lib_ext_secret_key_new((*C.uchar)(secretKeyByteData), (*C.uchar)(chainCodeByteData), derivationPath.pointer(), &p)
@@ -58,14 +67,14 @@ func NewExtendedSecretKey(secretKeyBytes []byte, chainCode []byte, derivationPat
 		return nil, err.error()
 	}
 
-	e := &extendedSecretKey{p: p}
+	e := &extendedSecretKey{p: p, chainCode: bytes.Clone(chainCode)}
 	return newExtendedSecretKey(e), nil
 }
 
 // DeriveMaster derives root ExtendedSecretKey from seed bytes
 func DeriveMaster(seed []byte) (ExtendedSecretKey, error) {
 	seedByteData := C.CBytes(seed)
-	defer C.free(unsafe.Pointer(seedByteData))
+	defer freeSecret(seedByteData, len(seed))
 
 	var p C.ExtSecretKeyPtr
 	errPtr := C.ergo_lib_ext_secret_key_derive_master((*C.uchar)(seedByteData), &p)
@@ -73,25 +82,43 @@ func DeriveMaster(seed []byte) (ExtendedSecretKey, error) {
 	if err.isError() {
 		return nil, err.error()
 	}
-	es := &extendedSecretKey{p: p}
+	es := &extendedSecretKey{p: p, chainCode: masterChainCode(seed)}
 	return newExtendedSecretKey(es), nil
 }
 
 func (e *extendedSecretKey) Child(index string) (ExtendedSecretKey, error) {
+	if e.p == nil {
+		return nil, ErrClosed
+	}
+
+	childIndex, err := parseChildIndex(index)
+	if err != nil {
+		return nil, err
+	}
+
 	indexStr := C.CString(index)
 	defer C.free(unsafe.Pointer(indexStr))
 
 	var p C.ExtSecretKeyPtr
 	errPtr := C.ergo_lib_ext_secret_key_child(e.p, indexStr, &p)
-	err := newError(errPtr)
-	if err.isError() {
-		return nil, err.error()
+	cErr := newError(errPtr)
+	if cErr.isError() {
+		return nil, cErr.error()
 	}
 	es := &extendedSecretKey{p: p}
-	return newExtendedSecretKey(es), nil
+	child := newExtendedSecretKey(es)
+	if err := e.childMetadata(es, childIndex); err != nil {
+		child.Close()
+		return nil, err
+	}
+	return child, nil
 }
 
 func (e *extendedSecretKey) Path() DerivationPath {
+	if e.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.DerivationPathPtr
 	C.ergo_lib_ext_secret_key_path(e.p, &p)
 	d := &derivationPath{p: p}
@@ -99,6 +126,10 @@ func (e *extendedSecretKey) Path() DerivationPath {
 }
 
 func (e *extendedSecretKey) SecretKey() SecretKey {
+	if e.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.SecretKeyPtr
 	C.ergo_lib_ext_secret_key_get_secret_key(e.p, &p)
 	s := &secretKey{p: p}
@@ -106,23 +137,111 @@ func (e *extendedSecretKey) SecretKey() SecretKey {
 }
 
 func (e *extendedSecretKey) ExtendedPublicKey() ExtendedPublicKey {
+	if e.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ExtPubKeyPtr
 	C.ergo_lib_ext_secret_key_public_key(e.p, &p)
-	ep := &extendedPublicKey{p: p}
+	path := e.Path()
+	defer path.Close()
+	// a path that can not be parsed is reported by the methods of the ExtendedPublicKey relying on it
+	indices, err := parseDerivationPath(path.String())
+
+	ep := &extendedPublicKey{p: p, chainCode: bytes.Clone(e.chainCode), path: indices, parentFingerprint: e.parentFingerprint, pathErr: err}
 	return newExtendedPublicKey(ep)
 }
 
+// Derive walks derivationPath from e with Child, so every level is derived once by ergo-lib-c and its chain
+// code is computed in Go along the way
 func (e *extendedSecretKey) Derive(derivationPath DerivationPath) (ExtendedSecretKey, error) {
+	if e.p == nil {
+		return nil, ErrClosed
+	}
+	parentPath := e.Path()
+	defer parentPath.Close()
+	parent, err := parseDerivationPath(parentPath.String())
+	if err != nil {
+		return nil, err
+	}
+	path, err := parseDerivationPath(derivationPath.String())
+	if err != nil {
+		return nil, err
+	}
+	indices, err := childPath(parent, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return e.copy(derivationPath)
+	}
+
+	var key ExtendedSecretKey = e
+	for _, index := range indices {
+		child, err := key.Child(formatChildIndex(index))
+		if key != ExtendedSecretKey(e) {
+			key.Destroy()
+		}
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+// copy returns a new ExtendedSecretKey for e, derivationPath is the path of e
+func (e *extendedSecretKey) copy(derivationPath DerivationPath) (ExtendedSecretKey, error) {
 	var p C.ExtSecretKeyPtr
 	errPtr := C.ergo_lib_ext_secret_key_derive(e.p, derivationPath.pointer(), &p)
 	err := newError(errPtr)
 	if err.isError() {
 		return nil, err.error()
 	}
-	es := &extendedSecretKey{p: p}
+	es := &extendedSecretKey{p: p, chainCode: bytes.Clone(e.chainCode), parentFingerprint: e.parentFingerprint}
 	return newExtendedSecretKey(es), nil
 }
 
+func (e *extendedSecretKey) Destroy() {
+	clear(e.chainCode)
+	e.Close()
+}
+
+// childMetadata sets the chain code and the parent fingerprint of child, the child at index of e
+func (e *extendedSecretKey) childMetadata(child *extendedSecretKey, index uint32) error {
+	publicKey, err := e.publicKey()
+	if err != nil {
+		return err
+	}
+	child.parentFingerprint = fingerprint(publicKey)
+
+	if index < hardenedIndex {
+		child.chainCode = childChainCode(e.chainCode, publicKey, index)
+		return nil
+	}
+	secret := e.SecretKey()
+	defer secret.Close()
+	return secret.WithBytes(func(secretBytes []byte) error {
+		child.chainCode = childChainCode(e.chainCode, secretBytes, index)
+		return nil
+	})
+}
+
+// publicKey returns the compressed public key of e
+func (e *extendedSecretKey) publicKey() ([]byte, error) {
+	publicKey := e.ExtendedPublicKey()
+	defer publicKey.Close()
+	return publicKey.(*extendedPublicKey).publicKey()
+}
+
+func (e *extendedSecretKey) Close() {
+	if e.p != nil {
+		runtime.SetFinalizer(e, nil)
+		finalizeExtendedSecretKey(e)
+		e.p = nil
+	}
+}
+
 func finalizeExtendedSecretKey(e *extendedSecretKey) {
 	C.ergo_lib_ext_secret_key_delete(e.p)
 }
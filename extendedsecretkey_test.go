@@ -26,3 +26,14 @@ func TestExtendedSecretKey(t *testing.T) {
 
 	assert.Equal(t, "9gYRhhA9TcFv6xWGwTBPLBJzyW1Hv3EiDzXqoivWYjq8TowWJ1h", nextChangeSecret.ExtendedPublicKey().Address().Base58(MainnetPrefix))
 }
+
+func TestExtendedSecretKey_Destroy(t *testing.T) {
+	root, err := DeriveMaster(MnemonicToSeed("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", ""))
+	assert.NoError(t, err)
+	chainCode := root.(*extendedSecretKey).chainCode
+	assert.NotEqual(t, make([]byte, len(chainCode)), chainCode)
+
+	root.Destroy()
+	assert.Equal(t, make([]byte, len(chainCode)), chainCode)
+	assert.PanicsWithValue(t, ErrClosed, func() { root.SecretKey() })
+}
@@ -7,6 +7,7 @@ import "C"
 import (
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -16,6 +17,8 @@ type UnsignedInput interface {
 	BoxId() BoxId
 	// ContextExtension returns the ContextExtension of the UnsignedInput
 	ContextExtension() ContextExtension
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.UnsignedInputPtr
 }
 
@@ -29,6 +32,10 @@ func newUnsignedInput(u *unsignedInput) UnsignedInput {
 }
 
 func (u *unsignedInput) BoxId() BoxId {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxIdPtr
 
 	C.ergo_lib_unsigned_input_box_id(u.p, &p)
@@ -39,6 +46,10 @@ func (u *unsignedInput) BoxId() BoxId {
 }
 
 func (u *unsignedInput) ContextExtension() ContextExtension {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ContextExtensionPtr
 
 	C.ergo_lib_unsigned_input_context_extension(u.p, &p)
@@ -49,9 +60,21 @@ func (u *unsignedInput) ContextExtension() ContextExtension {
 }
 
 func (u *unsignedInput) pointer() C.UnsignedInputPtr {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	return u.p
 }
 
+func (u *unsignedInput) Close() {
+	if u.p != nil {
+		runtime.SetFinalizer(u, nil)
+		finalizeUnsignedInput(u)
+		u.p = nil
+	}
+}
+
 func finalizeUnsignedInput(u *unsignedInput) {
 	C.ergo_lib_unsigned_input_delete(u.p)
 }
@@ -62,6 +85,8 @@ type Input interface {
 	BoxId() BoxId
 	// SpendingProof returns spending proof of Input as ProverResult
 	SpendingProof() ProverResult
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.InputPtr
 }
 
@@ -75,6 +100,10 @@ func newInput(i *input) Input {
 }
 
 func (i *input) BoxId() BoxId {
+	if i.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxIdPtr
 
 	C.ergo_lib_input_box_id(i.p, &p)
@@ -85,6 +114,10 @@ func (i *input) BoxId() BoxId {
 }
 
 func (i *input) SpendingProof() ProverResult {
+	if i.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ProverResultPtr
 
 	C.ergo_lib_input_spending_proof(i.p, &p)
@@ -95,9 +128,21 @@ func (i *input) SpendingProof() ProverResult {
 }
 
 func (i *input) pointer() C.InputPtr {
+	if i.p == nil {
+		panic(ErrClosed)
+	}
+
 	return i.p
 }
 
+func (i *input) Close() {
+	if i.p != nil {
+		runtime.SetFinalizer(i, nil)
+		finalizeInput(i)
+		i.p = nil
+	}
+}
+
 func finalizeInput(i *input) {
 	C.ergo_lib_input_delete(i.p)
 }
@@ -110,6 +155,8 @@ type ProverResult interface {
 	ContextExtension() ContextExtension
 	// Json representation as text (compatible with Ergo Node/Explorer API, numbers are encoded as numbers)
 	Json() (string, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type proverResult struct {
@@ -122,6 +169,10 @@ func newProverResult(pr *proverResult) ProverResult {
 }
 
 func (pr *proverResult) Bytes() []byte {
+	if pr.p == nil {
+		panic(ErrClosed)
+	}
+
 	proofLength := C.ergo_lib_prover_result_proof_len(pr.p)
 
 	output := C.malloc(C.uintptr_t(proofLength))
@@ -135,6 +186,10 @@ func (pr *proverResult) Bytes() []byte {
 }
 
 func (pr *proverResult) ContextExtension() ContextExtension {
+	if pr.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ContextExtensionPtr
 
 	C.ergo_lib_prover_result_context_extension(pr.p, &p)
@@ -145,6 +200,10 @@ func (pr *proverResult) ContextExtension() ContextExtension {
 }
 
 func (pr *proverResult) Json() (string, error) {
+	if pr.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_prover_result_to_json(pr.p, &outStr)
@@ -160,6 +219,14 @@ func (pr *proverResult) Json() (string, error) {
 	return result, nil
 }
 
+func (pr *proverResult) Close() {
+	if pr.p != nil {
+		runtime.SetFinalizer(pr, nil)
+		finalizeProverResult(pr)
+		pr.p = nil
+	}
+}
+
 func finalizeProverResult(pr *proverResult) {
 	C.ergo_lib_prover_result_delete(pr.p)
 }
@@ -174,10 +241,13 @@ type UnsignedInputs interface {
 	Add(unsignedInput UnsignedInput)
 	// All returns an iterator over all UnsignedInput inside the collection
 	All() iter.Seq2[int, UnsignedInput]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type unsignedInputs struct {
-	p C.UnsignedInputsPtr
+	p  C.UnsignedInputsPtr
+	mu sync.RWMutex
 }
 
 func newUnsignedInputs(u *unsignedInputs) UnsignedInputs {
@@ -196,11 +266,37 @@ func NewUnsignedInputs() UnsignedInputs {
 }
 
 func (u *unsignedInputs) Len() int {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
+	return u.len()
+}
+
+func (u *unsignedInputs) len() int {
+	if u.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_unsigned_inputs_len(u.p)
 	return int(res)
 }
 
 func (u *unsignedInputs) Get(index int) (UnsignedInput, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.get(index)
+}
+
+func (u *unsignedInputs) get(index int) (UnsignedInput, error) {
+	if u.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.UnsignedInputPtr
 
 	res := C.ergo_lib_unsigned_inputs_get(u.p, C.uintptr_t(index), &p)
@@ -218,20 +314,43 @@ func (u *unsignedInputs) Get(index int) (UnsignedInput, error) {
 }
 
 func (u *unsignedInputs) Add(unsignedInput UnsignedInput) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_unsigned_inputs_add(unsignedInput.pointer(), u.p)
 }
 
 func (u *unsignedInputs) All() iter.Seq2[int, UnsignedInput] {
-	return func(yield func(int, UnsignedInput) bool) {
-		for i := 0; i < u.Len(); i++ {
-			tk, err := u.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
+	return readLockedAll(u, u.len, u.get)
+}
+
+func (u *unsignedInputs) rlock() {
+	u.mu.RLock()
+}
+
+func (u *unsignedInputs) runlock() {
+	u.mu.RUnlock()
+}
+
+func (u *unsignedInputs) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.p != nil {
+		runtime.SetFinalizer(u, nil)
+		finalizeUnsignedInputs(u)
+		u.p = nil
 	}
 }
 
@@ -249,10 +368,13 @@ type Inputs interface {
 	Add(input Input)
 	// All returns an iterator over all Input inside the collection
 	All() iter.Seq2[int, Input]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type inputs struct {
-	p C.InputsPtr
+	p  C.InputsPtr
+	mu sync.RWMutex
 }
 
 func newInputs(i *inputs) Inputs {
@@ -271,11 +393,37 @@ func NewInputs() Inputs {
 }
 
 func (i *inputs) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.p == nil {
+		panic(ErrClosed)
+	}
+
+	return i.len()
+}
+
+func (i *inputs) len() int {
+	if i.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_inputs_len(i.p)
 	return int(res)
 }
 
 func (i *inputs) Get(index int) (Input, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.get(index)
+}
+
+func (i *inputs) get(index int) (Input, error) {
+	if i.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.InputPtr
 
 	res := C.ergo_lib_inputs_get(i.p, C.uintptr_t(index), &p)
@@ -293,20 +441,43 @@ func (i *inputs) Get(index int) (Input, error) {
 }
 
 func (i *inputs) Add(input Input) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_inputs_add(input.pointer(), i.p)
 }
 
 func (i *inputs) All() iter.Seq2[int, Input] {
-	return func(yield func(int, Input) bool) {
-		for j := 0; j < i.Len(); j++ {
-			tk, err := i.Get(j)
-			if err != nil {
-				return
-			}
-			if !yield(j, tk) {
-				return
-			}
-		}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if i.p == nil {
+		panic(ErrClosed)
+	}
+
+	return readLockedAll(i, i.len, i.get)
+}
+
+func (i *inputs) rlock() {
+	i.mu.RLock()
+}
+
+func (i *inputs) runlock() {
+	i.mu.RUnlock()
+}
+
+func (i *inputs) Close() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.p != nil {
+		runtime.SetFinalizer(i, nil)
+		finalizeInputs(i)
+		i.p = nil
 	}
 }
 
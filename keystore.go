@@ -0,0 +1,313 @@
+package ergo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"os"
+)
+
+// ErrWrongPassword is returned when a Keystore is unlocked with a wrong password
+var ErrWrongPassword = errors.New("ergo: wrong keystore password")
+
+// ErrKeystoreSecretKeys is returned when a Keystore holding secret keys is unlocked as ExtendedSecretKey
+var ErrKeystoreSecretKeys = errors.New("ergo: keystore holds secret keys, not a seed")
+
+// key derivation functions of KeystoreParams
+const (
+	// KeystorePBKDF2 derives the key with PBKDF2 and HMAC-SHA256, it is the key derivation of the Ergo node
+	KeystorePBKDF2 = "HmacSHA256"
+	// KeystoreScrypt derives the key with scrypt
+	KeystoreScrypt = "scrypt"
+)
+
+// content of a Keystore
+const (
+	keystoreSeed       = ""
+	keystoreSecretKeys = "secretKeys"
+)
+
+const (
+	keystoreSaltLength = 32
+	keystoreIVLength   = 16
+	secretKeyLength    = 32
+)
+
+// limits of KeystoreParams, so a Keystore file cannot make unlocking it take unbounded time or memory
+const (
+	// maxKeystoreIterations is ten times the PBKDF2 iteration count of the Ergo node
+	maxKeystoreIterations = 10 * 128000
+	// maxKeystoreScryptCost limits N * r * p of scrypt to 2^23, e.g. N = 2^20 with r = 8 and p = 1 which needs
+	// 1 GiB of memory
+	maxKeystoreScryptCost = 1 << 23
+)
+
+var (
+	// NodeKeystoreParams are the KeystoreParams of the Ergo node, a Keystore created with them
+	// from a seed can be used as secret storage of the node
+	NodeKeystoreParams = KeystoreParams{Prf: KeystorePBKDF2, C: 128000, DkLen: 256}
+	// ScryptKeystoreParams are KeystoreParams using scrypt with N = 2^16, r = 8 and p = 1
+	ScryptKeystoreParams = KeystoreParams{Prf: KeystoreScrypt, C: 1 << 16, R: 8, P: 1, DkLen: 256}
+)
+
+// KeystoreParams are the parameters of the key derivation of a Keystore
+type KeystoreParams struct {
+	// Prf is the key derivation function, KeystorePBKDF2 or KeystoreScrypt
+	Prf string `json:"prf"`
+	// C is the iteration count of PBKDF2 or the cost parameter N of scrypt. PBKDF2 allows at most 1280000
+	// iterations, ten times those of the Ergo node, and scrypt at most a product N * R * P of 2^23
+	C int `json:"c"`
+	// R is the block size parameter of scrypt
+	R int `json:"r,omitempty"`
+	// P is the parallelization parameter of scrypt
+	P int `json:"p,omitempty"`
+	// DkLen is the length of the derived key in bits, 128, 192 or 256
+	DkLen int `json:"dkLen"`
+}
+
+// Keystore holds a seed or secret keys encrypted with AES-GCM under a key derived from a password. Its JSON encoding
+// is the format of the secret storage of the Ergo node, a Keystore created with NewKeystore and NodeKeystoreParams
+// can be used by the node and the secret storage of the node can be unlocked as Keystore
+type Keystore struct {
+	CipherText   string         `json:"cipherText"`
+	Salt         string         `json:"salt"`
+	IV           string         `json:"iv"`
+	AuthTag      string         `json:"authTag"`
+	CipherParams KeystoreParams `json:"cipherParams"`
+	// UsePre1627KeyDerivation is set by the Ergo node for seeds of wallets created before node version 4.0.105,
+	// whose keys are derived in a non-standard way. Such keystores can not be unlocked as ExtendedSecretKey
+	UsePre1627KeyDerivation bool `json:"usePre1627KeyDerivation"`
+	// Content is empty for a seed and secretKeys for secret keys, it is unknown to the Ergo node
+	Content string `json:"content,omitempty"`
+}
+
+// NewKeystore encrypts seed with password, e.g. the seed returned by MnemonicToSeed
+func NewKeystore(seed []byte, password string, params KeystoreParams) (*Keystore, error) {
+	k := &Keystore{Content: keystoreSeed}
+	if err := k.encrypt(seed, password, params); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewKeystoreFromMnemonic encrypts the seed of mnemonicPhrase with password, see MnemonicToSeed
+func NewKeystoreFromMnemonic(mnemonicPhrase string, mnemonicPassword string, password string, params KeystoreParams) (*Keystore, error) {
+	seed := MnemonicToSeed(mnemonicPhrase, mnemonicPassword)
+	defer clear(seed)
+	return NewKeystore(seed, password, params)
+}
+
+// NewKeystoreFromSecretKeys encrypts secrets with password
+func NewKeystoreFromSecretKeys(secrets SecretKeys, password string, params KeystoreParams) (*Keystore, error) {
+	plaintext := make([]byte, 0, secrets.Len()*secretKeyLength)
+	defer func() { clear(plaintext) }()
+	for _, secret := range secrets.All() {
+		err := secret.WithBytes(func(secretBytes []byte) error {
+			plaintext = append(plaintext, secretBytes...)
+			return nil
+		})
+		secret.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	k := &Keystore{Content: keystoreSecretKeys}
+	if err := k.encrypt(plaintext, password, params); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// ReadKeystoreFile reads the Keystore stored as JSON at path
+func ReadKeystoreFile(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var k Keystore
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("keystore %s: %w", path, err)
+	}
+	return &k, nil
+}
+
+// WriteFile stores the Keystore as JSON at path, readable by the owner only
+func (k *Keystore) WriteFile(path string) error {
+	data, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// UnlockWallet decrypts the Keystore and returns a Wallet holding its secret keys. For a seed the Wallet holds
+// the master key like NewWallet, use UnlockExtendedSecretKey with Account to sign for the EIP-3 addresses
+func (k *Keystore) UnlockWallet(password string) (Wallet, error) {
+	plaintext, err := k.Export(password)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	secrets := NewSecretKeys()
+	defer secrets.Close()
+	if k.Content == keystoreSecretKeys {
+		for i := 0; i < len(plaintext); i += secretKeyLength {
+			secret, err := NewSecretKeyFromBytes(plaintext[i : i+secretKeyLength])
+			if err != nil {
+				return nil, err
+			}
+			secrets.Add(secret)
+			secret.Close()
+		}
+		return NewWalletFromSecretKeys(secrets), nil
+	}
+
+	master, err := k.masterKey(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	defer master.Destroy()
+	secret := master.SecretKey()
+	defer secret.Close()
+	secrets.Add(secret)
+	return NewWalletFromSecretKeys(secrets), nil
+}
+
+// UnlockExtendedSecretKey decrypts the seed of the Keystore and returns the master key derived from it
+func (k *Keystore) UnlockExtendedSecretKey(password string) (ExtendedSecretKey, error) {
+	if k.Content == keystoreSecretKeys {
+		return nil, ErrKeystoreSecretKeys
+	}
+	seed, err := k.Export(password)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	return k.masterKey(seed)
+}
+
+// Export decrypts the Keystore and returns the seed or the concatenated 32 byte secret keys.
+// The caller should clear the returned slice once done
+func (k *Keystore) Export(password string) ([]byte, error) {
+	key, err := k.CipherParams.deriveKey(password, k.Salt)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	iv, err := hex.DecodeString(k.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore iv: %w", err)
+	}
+	aead, err := newKeystoreAEAD(key, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(k.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore cipher text: %w", err)
+	}
+	authTag, err := hex.DecodeString(k.AuthTag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore auth tag: %w", err)
+	}
+
+	plaintext, err := aead.Open(nil, iv, append(cipherText, authTag...), nil)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	if k.Content == keystoreSecretKeys && len(plaintext)%secretKeyLength != 0 {
+		clear(plaintext)
+		return nil, errors.New("keystore secret keys are not a multiple of 32 bytes")
+	}
+	return plaintext, nil
+}
+
+// ChangePassword encrypts the content of the Keystore with newPassword, with a new salt and iv
+func (k *Keystore) ChangePassword(password string, newPassword string) error {
+	plaintext, err := k.Export(password)
+	if err != nil {
+		return err
+	}
+	defer clear(plaintext)
+	return k.encrypt(plaintext, newPassword, k.CipherParams)
+}
+
+func (k *Keystore) encrypt(plaintext []byte, password string, params KeystoreParams) error {
+	salt := make([]byte, keystoreSaltLength)
+	iv := make([]byte, keystoreIVLength)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return err
+	}
+
+	key, err := params.deriveKey(password, hex.EncodeToString(salt))
+	if err != nil {
+		return err
+	}
+	defer clear(key)
+	aead, err := newKeystoreAEAD(key, len(iv))
+	if err != nil {
+		return err
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, nil)
+	tagStart := len(sealed) - aead.Overhead()
+	k.CipherText = hex.EncodeToString(sealed[:tagStart])
+	k.AuthTag = hex.EncodeToString(sealed[tagStart:])
+	k.Salt = hex.EncodeToString(salt)
+	k.IV = hex.EncodeToString(iv)
+	k.CipherParams = params
+	return nil
+}
+
+func (k *Keystore) masterKey(seed []byte) (ExtendedSecretKey, error) {
+	if k.UsePre1627KeyDerivation {
+		return nil, errors.New("keystore uses the pre 1627 key derivation of the Ergo node which is not supported")
+	}
+	return DeriveMaster(seed)
+}
+
+func (p KeystoreParams) deriveKey(password string, salt string) ([]byte, error) {
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	if p.DkLen != 128 && p.DkLen != 192 && p.DkLen != 256 {
+		return nil, fmt.Errorf("unsupported keystore key length %d", p.DkLen)
+	}
+
+	switch p.Prf {
+	case KeystorePBKDF2:
+		if p.C < 1 || p.C > maxKeystoreIterations {
+			return nil, fmt.Errorf("keystore iteration count %d must be between 1 and %d", p.C, maxKeystoreIterations)
+		}
+		return pbkdf2.Key([]byte(password), saltBytes, p.C, p.DkLen/8, sha256.New), nil
+	case KeystoreScrypt:
+		if p.C < 1 || p.R < 1 || p.P < 1 || p.C > maxKeystoreScryptCost/p.R/p.P {
+			return nil, fmt.Errorf("keystore scrypt parameters N = %d, r = %d and p = %d must be positive with a product of at most %d", p.C, p.R, p.P, maxKeystoreScryptCost)
+		}
+		return scrypt.Key([]byte(password), saltBytes, p.C, p.R, p.P, p.DkLen/8)
+	default:
+		return nil, fmt.Errorf("unsupported keystore key derivation %q", p.Prf)
+	}
+}
+
+func newKeystoreAEAD(key []byte, ivLength int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	// the Ergo node uses 16 byte ivs instead of the standard 12 bytes
+	return cipher.NewGCMWithNonceSize(block, ivLength)
+}
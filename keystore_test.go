@@ -0,0 +1,99 @@
+package ergo
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+// fast parameters for the tests only
+var testKeystoreParams = KeystoreParams{Prf: KeystoreScrypt, C: 1 << 10, R: 8, P: 1, DkLen: 256}
+
+func TestKeystore_Export(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x2a}, 64)
+
+	for _, params := range []KeystoreParams{testKeystoreParams, {Prf: KeystorePBKDF2, C: 1000, DkLen: 256}} {
+		k, err := NewKeystore(seed, "password", params)
+		assert.NoError(t, err)
+		assert.Len(t, k.IV, 32)
+		assert.Len(t, k.AuthTag, 32)
+
+		exported, err := k.Export("password")
+		assert.NoError(t, err)
+		assert.Equal(t, seed, exported)
+
+		_, err = k.Export("wrong")
+		assert.ErrorIs(t, err, ErrWrongPassword)
+	}
+}
+
+func TestKeystore_ChangePassword(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x2a}, 64)
+	k, _ := NewKeystore(seed, "password", testKeystoreParams)
+	salt := k.Salt
+
+	assert.ErrorIs(t, k.ChangePassword("wrong", "new"), ErrWrongPassword)
+	assert.NoError(t, k.ChangePassword("password", "new"))
+	assert.NotEqual(t, salt, k.Salt)
+
+	_, err := k.Export("password")
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	exported, err := k.Export("new")
+	assert.NoError(t, err)
+	assert.Equal(t, seed, exported)
+}
+
+func TestKeystore_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	k, _ := NewKeystoreFromMnemonic("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "", "password", testKeystoreParams)
+	assert.NoError(t, k.WriteFile(path))
+
+	read, err := ReadKeystoreFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, k, read)
+
+	master, err := read.UnlockExtendedSecretKey("password")
+	assert.NoError(t, err)
+	accountPath, _ := NewDerivationPath(0, []uint32{0})
+	key, _ := master.Derive(accountPath)
+	assert.Equal(t, "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r", key.ExtendedPublicKey().Address().Base58(MainnetPrefix))
+
+	w, err := read.UnlockWallet("password")
+	assert.NoError(t, err)
+	assert.NotNil(t, w)
+}
+
+func TestNewKeystoreFromSecretKeys(t *testing.T) {
+	secret := NewSecretKey()
+	secrets := NewSecretKeys()
+	secrets.Add(secret)
+
+	k, err := NewKeystoreFromSecretKeys(secrets, "password", testKeystoreParams)
+	assert.NoError(t, err)
+	exported, err := k.Export("password")
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Bytes(), exported)
+
+	_, err = k.UnlockExtendedSecretKey("password")
+	assert.ErrorIs(t, err, ErrKeystoreSecretKeys)
+	w, err := k.UnlockWallet("password")
+	assert.NoError(t, err)
+	assert.NotNil(t, w)
+}
+
+func TestKeystoreParams_Limits(t *testing.T) {
+	k, err := NewKeystore(bytes.Repeat([]byte{0x2a}, 64), "password", testKeystoreParams)
+	assert.NoError(t, err)
+
+	k.CipherParams = KeystoreParams{Prf: KeystorePBKDF2, C: 1 << 30, DkLen: 256}
+	_, err = k.Export("password")
+	assert.ErrorContains(t, err, "keystore iteration count 1073741824 must be between 1 and 1280000")
+
+	k.CipherParams = KeystoreParams{Prf: KeystoreScrypt, C: 1 << 20, R: 8, P: 2, DkLen: 256}
+	_, err = k.Export("password")
+	assert.ErrorContains(t, err, "must be positive with a product of at most 8388608")
+	k.CipherParams.P = 0
+	_, err = k.Export("password")
+	assert.ErrorContains(t, err, "must be positive")
+}
@@ -0,0 +1,51 @@
+package ergo
+
+import (
+	"iter"
+	"slices"
+)
+
+// locker is implemented by values whose native state can change after creation, like collections,
+// hint bags and builders. Their own methods synchronise on an internal sync.RWMutex, functions taking
+// them as arguments hold a read lock through readLock while native code accesses them
+type locker interface {
+	rlock()
+	runlock()
+}
+
+// readLock read locks every distinct locker and returns a function releasing all of them
+func readLock(lockers ...locker) func() {
+	locked := make([]locker, 0, len(lockers))
+	for _, l := range lockers {
+		if slices.Contains(locked, l) {
+			continue
+		}
+		l.rlock()
+		locked = append(locked, l)
+	}
+	return func() {
+		for _, l := range locked {
+			l.runlock()
+		}
+	}
+}
+
+// readLockedAll iterates over the elements of the collection l, read by length and get without locking. l is read
+// locked while an element is read but not while it is yielded, so the loop body may use the collection.
+// The iteration ends at the first error or once l is closed
+func readLockedAll[T any](l locker, length func() int, get func(int) (T, error)) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; ; i++ {
+			unlock := readLock(l)
+			if i >= length() {
+				unlock()
+				return
+			}
+			element, err := get(i)
+			unlock()
+			if err != nil || !yield(i, element) {
+				return
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ package ergo
 import "C"
 import (
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -25,10 +26,13 @@ type MerkleProof interface {
 	Valid(expectedRoot []byte) bool
 	// ValidBase16 validates the MerkleProof against the provided base16 root hash
 	ValidBase16(expectedRoot string) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type merkleProof struct {
-	p C.MerkleProofPtr
+	p  C.MerkleProofPtr
+	mu sync.RWMutex
 }
 
 func newMerkleProof(m *merkleProof) MerkleProof {
@@ -70,6 +74,13 @@ func NewMerkleProofFromJson(json string) (MerkleProof, error) {
 }
 
 func (m *merkleProof) AddNode(hash []byte, side nodeSide) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.p == nil {
+		return ErrClosed
+	}
+
 	byteData := C.CBytes(hash)
 	defer C.free(unsafe.Pointer(byteData))
 
@@ -82,6 +93,13 @@ func (m *merkleProof) AddNode(hash []byte, side nodeSide) error {
 }
 
 func (m *merkleProof) Valid(expectedRoot []byte) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.p == nil {
+		panic(ErrClosed)
+	}
+
 	byteData := C.CBytes(expectedRoot)
 	defer C.free(unsafe.Pointer(byteData))
 	res := C.ergo_merkle_proof_valid(m.p, (*C.uchar)(byteData), C.uintptr_t(len(expectedRoot)))
@@ -89,6 +107,13 @@ func (m *merkleProof) Valid(expectedRoot []byte) bool {
 }
 
 func (m *merkleProof) ValidBase16(expectedRoot string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.p == nil {
+		panic(ErrClosed)
+	}
+
 	rootStr := C.CString(expectedRoot)
 	defer C.free(unsafe.Pointer(rootStr))
 	var res C.bool
@@ -100,6 +125,17 @@ func (m *merkleProof) ValidBase16(expectedRoot string) bool {
 	return bool(res)
 }
 
+func (m *merkleProof) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.p != nil {
+		runtime.SetFinalizer(m, nil)
+		finalizeMerkleProof(m)
+		m.p = nil
+	}
+}
+
 func finalizeMerkleProof(m *merkleProof) {
 	C.ergo_merkle_proof_delete(m.p)
 }
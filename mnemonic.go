@@ -4,21 +4,107 @@ package ergo
 #include "ergo.h"
 */
 import "C"
-import "unsafe"
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"math/big"
+	"strings"
+	"unsafe"
+)
 
 // MnemonicToSeed converts a mnemonic phrase into a mnemonic seed
 // mnemonicPassword is optional and is used to salt the seed
 func MnemonicToSeed(mnemonicPhrase string, mnemonicPassword string) []byte {
 	mnemonic := C.CString(mnemonicPhrase)
-	defer C.free(unsafe.Pointer(mnemonic))
+	defer freeSecret(unsafe.Pointer(mnemonic), len(mnemonicPhrase))
 
 	password := C.CString(mnemonicPassword)
-	defer C.free(unsafe.Pointer(password))
+	defer freeSecret(unsafe.Pointer(password), len(mnemonicPassword))
 
 	bytes := C.malloc(C.uintptr_t(512 / 8))
 	C.ergo_lib_mnemonic_to_seed(mnemonic, password, (*C.uint8_t)(bytes))
-	defer C.free(unsafe.Pointer(bytes))
+	defer freeSecret(bytes, 512/8)
 
 	result := C.GoBytes(bytes, C.int(512/8))
 	return result
 }
+
+var (
+	// ErrMnemonicLength is returned by ValidateMnemonic for a mnemonic that does not have 12, 15, 18, 21 or 24 words
+	ErrMnemonicLength = errors.New("ergo: mnemonic must have 12, 15, 18, 21 or 24 words")
+	// ErrMnemonicChecksum is returned by ValidateMnemonic for a mnemonic whose checksum does not match, usually
+	// because a word was mistyped for another word of the word list or the words are in the wrong order
+	ErrMnemonicChecksum = errors.New("ergo: mnemonic checksum mismatch")
+)
+
+// UnknownMnemonicWordError is returned by ValidateMnemonic for a word that is not in the word list
+type UnknownMnemonicWordError struct {
+	// Position is the position of the word in the mnemonic, starting at 1
+	Position int
+	Word     string
+}
+
+func (e *UnknownMnemonicWordError) Error() string {
+	return fmt.Sprintf("ergo: unknown mnemonic word %q at position %d", e.Word, e.Position)
+}
+
+// ValidateMnemonic checks mnemonicPhrase against the BIP-39 word list of language and verifies its checksum.
+// The languages of NewMnemonicGenerator are supported: english, chinese_simplified, chinese_traditional,
+// french, italian, japanese, korean and spanish. It returns an *UnknownMnemonicWordError, ErrMnemonicLength
+// or ErrMnemonicChecksum
+func ValidateMnemonic(mnemonicPhrase string, language string) error {
+	w, err := loadWordlist(language)
+	if err != nil {
+		return err
+	}
+
+	words := strings.Fields(norm.NFKD.String(mnemonicPhrase))
+	indices := make([]int, len(words))
+	for i, word := range words {
+		index, ok := w.index[word]
+		if !ok {
+			return &UnknownMnemonicWordError{Position: i + 1, Word: word}
+		}
+		indices[i] = index
+	}
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return ErrMnemonicLength
+	}
+
+	// every word holds 11 bits, the entropy is followed by a checksum of 1 bit per 32 bits of entropy
+	bits := new(big.Int)
+	for _, index := range indices {
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+	checksumBits := uint(len(words) * 11 / 33)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1))
+	entropy := make([]byte, checksumBits*4)
+	new(big.Int).Rsh(bits, checksumBits).FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	if uint64(hash[0]>>(8-checksumBits)) != checksum.Uint64() {
+		return ErrMnemonicChecksum
+	}
+	return nil
+}
+
+// SuggestMnemonicWords returns the words of the BIP-39 word list of language that start with prefix, in the order
+// of the word list, see ValidateMnemonic for the supported languages
+func SuggestMnemonicWords(prefix string, language string) ([]string, error) {
+	w, err := loadWordlist(language)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = norm.NFKD.String(strings.TrimSpace(prefix))
+	var suggestions []string
+	for _, word := range w.words {
+		if strings.HasPrefix(word, prefix) {
+			suggestions = append(suggestions, word)
+		}
+	}
+	return suggestions, nil
+}
@@ -17,3 +17,39 @@ func TestMnemonicToSeeWithPass(t *testing.T) {
 	seed := MnemonicToSeed(mnemonic, "password123")
 	assert.Equal(t, "dfe3088b88e2eb8588482e8c56d9cde497c4e1f63fd29b480cbb0ed0227331d51301cfc2d461acce642868ecb618a37b4fd75d48dc6189674c55fbafd807d69c", hex.EncodeToString(seed))
 }
+
+func TestValidateMnemonic(t *testing.T) {
+	assert.NoError(t, ValidateMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow", "english"))
+	assert.NoError(t, ValidateMnemonic("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "english"))
+
+	var unknownWord *UnknownMnemonicWordError
+	err := ValidateMnemonic("legal winner thank year wave sausage worth usefull legal winner thank yellow", "english")
+	assert.ErrorAs(t, err, &unknownWord)
+	assert.Equal(t, 8, unknownWord.Position)
+	assert.Equal(t, "usefull", unknownWord.Word)
+
+	assert.ErrorIs(t, ValidateMnemonic("legal winner thank year wave sausage worth useful legal winner thank", "english"), ErrMnemonicLength)
+	assert.ErrorIs(t, ValidateMnemonic("legal winner thank year wave sausage worth useful legal winner yellow thank", "english"), ErrMnemonicChecksum)
+	assert.Error(t, ValidateMnemonic("legal winner thank year wave sausage worth useful legal winner thank yellow", "klingon"))
+}
+
+func TestValidateMnemonic_Japanese(t *testing.T) {
+	// words separated by ideographic spaces
+	mnemonic := "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら"
+	assert.NoError(t, ValidateMnemonic(mnemonic, "japanese"))
+}
+
+func TestSuggestMnemonicWords(t *testing.T) {
+	suggestions, err := SuggestMnemonicWords("abs", "english")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"absent", "absorb", "abstract", "absurd"}, suggestions)
+
+	// composed input matches the decomposed word list
+	suggestions, err = SuggestMnemonicWords("ábac", "spanish")
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+
+	suggestions, err = SuggestMnemonicWords("xyz", "english")
+	assert.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
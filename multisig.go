@@ -0,0 +1,220 @@
+package ergo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MultisigSession coordinates signing a transaction whose inputs need the signatures of several participants,
+// e.g. an N-of-M threshold contract. Participants that commit sign the transaction, the others are simulated:
+//
+//  1. every participant is added with AddParticipant
+//  2. the signing participants call Commit
+//  3. the signing participants call Sign one after another, no participant can commit after the first Sign
+//  4. Transaction returns the signed transaction once every committed participant has signed
+//
+// The session is limited to a single process. ergo-lib-c can neither serialize hints nor tell the secret
+// commitments of a participant from the public ones, see TransactionHintsBag. The session therefore passes the
+// whole commitments of every participant to the others and its state cannot be saved, so the wallets of all
+// participants have to live in the same process and a session does not survive a restart. A participant only
+// uses the secret commitments of its own keys, those of the others are not used to sign.
+// Cosigners on other machines have to exchange their public commitments through the Ergo node instead.
+type MultisigSession struct {
+	mu           sync.Mutex
+	stateContext StateContext
+	unsignedTx   UnsignedTransaction
+	boxesToSpend Boxes
+	dataBoxes    Boxes
+	inputs       int
+	participants []*multisigParticipant
+	signing      bool
+	tx           Transaction
+}
+
+type multisigParticipant struct {
+	id          string
+	wallet      Wallet
+	proposition []byte
+	commitments TransactionHintsBag
+	signed      bool
+}
+
+// NewMultisigSession creates a MultisigSession for unsignedTx spending boxesToSpend
+func NewMultisigSession(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) *MultisigSession {
+	unsignedInputs := unsignedTx.UnsignedInputs()
+	defer unsignedInputs.Close()
+
+	return &MultisigSession{
+		stateContext: stateContext,
+		unsignedTx:   unsignedTx,
+		boxesToSpend: boxesToSpend,
+		dataBoxes:    dataBoxes,
+		inputs:       unsignedInputs.Len(),
+	}
+}
+
+// AddParticipant adds a participant identified by id, who signs with wallet for the P2PK address
+func (s *MultisigSession) AddParticipant(id string, wallet Wallet, address Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.signing {
+		return errors.New("multisig session is already signing")
+	}
+	if slices.ContainsFunc(s.participants, func(p *multisigParticipant) bool { return p.id == id }) {
+		return fmt.Errorf("multisig participant %q already exists", id)
+	}
+	proposition, err := p2pkProposition(address)
+	if err != nil {
+		return err
+	}
+
+	s.participants = append(s.participants, &multisigParticipant{id: id, wallet: wallet, proposition: proposition})
+	return nil
+}
+
+// Commit generates the commitments of the participant, it marks the participant as signer
+func (s *MultisigSession) Commit(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.participant(id)
+	if err != nil {
+		return err
+	}
+	if s.signing {
+		return errors.New("multisig session is already signing")
+	}
+	if p.commitments != nil {
+		return fmt.Errorf("multisig participant %q has already committed", id)
+	}
+
+	p.commitments, err = p.wallet.GenerateCommitments(s.stateContext, s.unsignedTx, s.boxesToSpend, s.dataBoxes)
+	return err
+}
+
+// Sign adds the signature of the participant to the transaction
+func (s *MultisigSession) Sign(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.participant(id)
+	if err != nil {
+		return err
+	}
+	if p.commitments == nil {
+		return fmt.Errorf("multisig participant %q has not committed", id)
+	}
+	if p.signed {
+		return fmt.Errorf("multisig participant %q has already signed", id)
+	}
+	s.signing = true
+
+	var extracted TransactionHintsBag
+	if s.tx != nil {
+		extracted, err = s.extractHints()
+		if err != nil {
+			return err
+		}
+		defer extracted.Close()
+	}
+
+	txHints := NewTransactionHintsBag()
+	defer txHints.Close()
+	for input := range s.inputs {
+		bag := NewHintsBag()
+		if extracted != nil {
+			extractedHints := extracted.AllHintsForInput(uint32(input))
+			for _, hint := range extractedHints.All() {
+				bag.Add(hint)
+				hint.Close()
+			}
+			extractedHints.Close()
+		}
+		for _, other := range s.participants {
+			if other.commitments == nil || other.signed {
+				continue
+			}
+			// the public and secret commitments cannot be told apart, the prover only takes the secret
+			// commitments of the keys it holds
+			hints := other.commitments.AllHintsForInput(uint32(input))
+			for _, hint := range hints.All() {
+				bag.Add(hint)
+				hint.Close()
+			}
+			hints.Close()
+		}
+		txHints.AddHintsForInput(uint32(input), bag)
+		bag.Close()
+	}
+
+	tx, err := p.wallet.SignTransactionMulti(s.stateContext, s.unsignedTx, s.boxesToSpend, s.dataBoxes, txHints)
+	if err != nil {
+		return err
+	}
+
+	s.tx = tx
+	p.signed = true
+	return nil
+}
+
+// extractHints extracts the proofs of the participants that already signed from the partially signed transaction
+func (s *MultisigSession) extractHints() (TransactionHintsBag, error) {
+	realPropositions := NewPropositions()
+	defer realPropositions.Close()
+	simulatedPropositions := NewPropositions()
+	defer simulatedPropositions.Close()
+
+	for _, p := range s.participants {
+		var err error
+		switch {
+		case p.signed:
+			err = realPropositions.Add(p.proposition)
+		case p.commitments == nil:
+			err = simulatedPropositions.Add(p.proposition)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ExtractHintsFromSignedTransaction(s.tx, s.stateContext, s.boxesToSpend, s.dataBoxes, realPropositions, simulatedPropositions)
+}
+
+// Pending returns the ids of the participants that have committed but not signed yet
+func (s *MultisigSession) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []string
+	for _, p := range s.participants {
+		if p.commitments != nil && !p.signed {
+			pending = append(pending, p.id)
+		}
+	}
+	return pending
+}
+
+// Transaction returns the signed Transaction once every committed participant has signed
+func (s *MultisigSession) Transaction() (Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tx == nil || slices.ContainsFunc(s.participants, func(p *multisigParticipant) bool {
+		return p.commitments != nil && !p.signed
+	}) {
+		return nil, errors.New("multisig session is not signed by all committed participants")
+	}
+	return s.tx, nil
+}
+
+func (s *MultisigSession) participant(id string) (*multisigParticipant, error) {
+	for _, p := range s.participants {
+		if p.id == id {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown multisig participant %q", id)
+}
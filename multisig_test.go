@@ -0,0 +1,68 @@
+package ergo
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMultisigSession(t *testing.T) {
+	aliceByteSecret, _ := hex.DecodeString("e726ad60a073a49f7851f4d11a83de6c9c7f99e17314fcce560f00a51a8a3d18")
+	aliceSecret, _ := NewSecretKeyFromBytes(aliceByteSecret)
+	bobByteSecret, _ := hex.DecodeString("9e6616b4e44818d21b8dfdd5ea87eb822480e7856ab910d00f5834dc64db79b3")
+	bobSecret, _ := NewSecretKeyFromBytes(bobByteSecret)
+
+	// Pay 2 Script address of a multi_sig contract with contract { alicePK && bobPK }
+	multiSigAddress, _ := NewAddress("JryiCXrc7x5D8AhS9DYX1TDzW5C5mT6QyTMQaptF76EQkM15cetxtYKq3u6LymLZLVCyjtgbTKFcfuuX9LLi49Ec5m2p6cwsg5NyEsCQ7na83yEPN")
+	inputContract, _ := NewContractPayToAddress(multiSigAddress)
+	testTxId, _ := NewTxId("0000000000000000000000000000000000000000000000000000000000000000")
+	testInputBoxValue, _ := NewBoxValue(1000000000)
+	testInputBox, _ := NewBox(testInputBoxValue, 0, inputContract, testTxId, 0, NewTokens())
+
+	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
+	unspentBoxes := NewBoxes()
+	unspentBoxes.Add(testInputBox)
+	testContract, _ := NewContractPayToAddress(recipient)
+	outBoxValue := SafeUserMinBoxValue()
+	fee := SuggestedTxFee()
+	outbox, _ := NewBoxCandidateBuilder(outBoxValue, testContract, 0).Build()
+	txOutputs := NewBoxCandidates()
+	txOutputs.Add(outbox)
+	targetBalance, _ := SumOfBoxValues(outBoxValue, fee)
+	testBoxSelection, _ := NewSimpleBoxSelector().Select(unspentBoxes, targetBalance, NewTokens())
+	tx, _ := NewTxBuilder(testBoxSelection, txOutputs, 0, fee, recipient).Build()
+
+	testBlockHeaders := testBlockHeadersFromJson()
+	testBlockHeader, _ := testBlockHeaders.Get(0)
+	ctx, _ := NewStateContext(NewPreHeader(testBlockHeader), testBlockHeaders, DefaultParameters())
+	txDataInputs := NewBoxes()
+
+	sksAlice := NewSecretKeys()
+	sksAlice.Add(aliceSecret)
+	sksBob := NewSecretKeys()
+	sksBob.Add(bobSecret)
+
+	session := NewMultisigSession(ctx, tx, unspentBoxes, txDataInputs)
+	assert.NoError(t, session.AddParticipant("alice", NewWalletFromSecretKeys(sksAlice), aliceSecret.Address()))
+	assert.NoError(t, session.AddParticipant("bob", NewWalletFromSecretKeys(sksBob), bobSecret.Address()))
+	assert.Error(t, session.AddParticipant("bob", NewWalletFromSecretKeys(sksBob), bobSecret.Address()))
+	assert.Error(t, session.AddParticipant("carol", NewWalletFromSecretKeys(sksBob), multiSigAddress))
+
+	assert.Error(t, session.Sign("alice"))
+	assert.NoError(t, session.Commit("alice"))
+	assert.NoError(t, session.Commit("bob"))
+	assert.Error(t, session.Commit("bob"))
+	assert.Equal(t, []string{"alice", "bob"}, session.Pending())
+
+	assert.NoError(t, session.Sign("alice"))
+	_, incompleteErr := session.Transaction()
+	assert.Error(t, incompleteErr)
+	assert.Error(t, session.Commit("unknown"))
+
+	assert.NoError(t, session.Sign("bob"))
+	assert.Empty(t, session.Pending())
+
+	signedTx, txErr := session.Transaction()
+	assert.NoError(t, txErr)
+	assert.NoError(t, signedTx.Validate(ctx, unspentBoxes, txDataInputs))
+}
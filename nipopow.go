@@ -5,7 +5,9 @@ package ergo
 */
 import "C"
 import (
+	"context"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -17,6 +19,8 @@ type NipopowProof interface {
 	SuffixHead() PoPowHeader
 	// Json returns json representation of NipopowProof as text
 	Json() (string, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.NipopowProofPtr
 }
 
@@ -48,6 +52,10 @@ func NewNipopowProof(json string) (NipopowProof, error) {
 }
 
 func (p *nipopowProof) IsBetterThan(otherProof NipopowProof) (bool, error) {
+	if p.p == nil {
+		return false, ErrClosed
+	}
+
 	res := C.ergo_lib_nipopow_proof_is_better_than(p.p, otherProof.pointer())
 	err := newError(res.error)
 	if err.isError() {
@@ -57,6 +65,10 @@ func (p *nipopowProof) IsBetterThan(otherProof NipopowProof) (bool, error) {
 }
 
 func (p *nipopowProof) SuffixHead() PoPowHeader {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	var ptr C.PoPowHeaderPtr
 	C.ergo_lib_nipopow_proof_suffix_head(p.p, &ptr)
 	pp := &poPowHeader{p: ptr}
@@ -64,6 +76,10 @@ func (p *nipopowProof) SuffixHead() PoPowHeader {
 }
 
 func (p *nipopowProof) Json() (string, error) {
+	if p.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_nipopow_proof_to_json(p.p, &outStr)
@@ -80,9 +96,21 @@ func (p *nipopowProof) Json() (string, error) {
 }
 
 func (p *nipopowProof) pointer() C.NipopowProofPtr {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	return p.p
 }
 
+func (p *nipopowProof) Close() {
+	if p.p != nil {
+		runtime.SetFinalizer(p, nil)
+		finalizeNipopowProof(p)
+		p.p = nil
+	}
+}
+
 func finalizeNipopowProof(p *nipopowProof) {
 	C.ergo_lib_nipopow_proof_delete(p.p)
 }
@@ -96,10 +124,16 @@ type NipopowVerifier interface {
 	BestChain() BlockHeaders
 	// Process given NipopowProof
 	Process(newProof NipopowProof) error
+	// ProcessContext processes given NipopowProof like Process unless ctx is already done. The native processing
+	// cannot be interrupted, once started it runs to completion and its result is returned
+	ProcessContext(ctx context.Context, newProof NipopowProof) error
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type nipopowVerifier struct {
-	p C.NipopowVerifierPtr
+	p  C.NipopowVerifierPtr
+	mu sync.RWMutex
 }
 
 func newNipopowVerifier(n *nipopowVerifier) NipopowVerifier {
@@ -116,6 +150,13 @@ func NewNipopowVerifier(genesisBlockId BlockId) NipopowVerifier {
 }
 
 func (n *nipopowVerifier) BestProof() NipopowProof {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.NipopowProofPtr
 	C.ergo_lib_nipopow_verifier_best_proof(n.p, &p)
 	np := &nipopowProof{p: p}
@@ -123,6 +164,13 @@ func (n *nipopowVerifier) BestProof() NipopowProof {
 }
 
 func (n *nipopowVerifier) BestChain() BlockHeaders {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BlockHeadersPtr
 	C.ergo_lib_nipopow_verifier_best_chain(n.p, &p)
 	bh := &blockHeaders{p: p}
@@ -130,6 +178,13 @@ func (n *nipopowVerifier) BestChain() BlockHeaders {
 }
 
 func (n *nipopowVerifier) Process(newProof NipopowProof) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.p == nil {
+		return ErrClosed
+	}
+
 	errPtr := C.ergo_lib_nipopow_verifier_process(n.p, newProof.pointer())
 	err := newError(errPtr)
 	if err.isError() {
@@ -138,6 +193,24 @@ func (n *nipopowVerifier) Process(newProof NipopowProof) error {
 	return nil
 }
 
+func (n *nipopowVerifier) ProcessContext(ctx context.Context, newProof NipopowProof) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return n.Process(newProof)
+}
+
+func (n *nipopowVerifier) Close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.p != nil {
+		runtime.SetFinalizer(n, nil)
+		finalizeNipopowVerifier(n)
+		n.p = nil
+	}
+}
+
 func finalizeNipopowVerifier(n *nipopowVerifier) {
 	C.ergo_lib_nipopow_verifier_delete(n.p)
 }
@@ -155,6 +228,8 @@ type PoPowHeader interface {
 	Json() (string, error)
 	// Equals checks if provided PoPowHeader is same
 	Equals(poPowHeader PoPowHeader) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.PoPowHeaderPtr
 }
 
@@ -186,6 +261,10 @@ func NewPoPowHeader(json string) (PoPowHeader, error) {
 }
 
 func (p *poPowHeader) Header() (BlockHeader, error) {
+	if p.p == nil {
+		return nil, ErrClosed
+	}
+
 	var ptr C.BlockHeaderPtr
 	errPtr := C.ergo_lib_popow_header_get_header(p.p, &ptr)
 	err := newError(errPtr)
@@ -197,6 +276,10 @@ func (p *poPowHeader) Header() (BlockHeader, error) {
 }
 
 func (p *poPowHeader) Interlinks() (BlockIds, error) {
+	if p.p == nil {
+		return nil, ErrClosed
+	}
+
 	var ptr C.BlockIdsPtr
 	errPtr := C.ergo_lib_popow_header_get_interlinks(p.p, &ptr)
 	err := newError(errPtr)
@@ -208,6 +291,10 @@ func (p *poPowHeader) Interlinks() (BlockIds, error) {
 }
 
 func (p *poPowHeader) InterlinksProof() (BatchMerkleProof, error) {
+	if p.p == nil {
+		return nil, ErrClosed
+	}
+
 	var ptr C.BatchMerkleProofPtr
 	errPtr := C.ergo_lib_popow_header_get_interlinks_proof(p.p, &ptr)
 	err := newError(errPtr)
@@ -219,11 +306,19 @@ func (p *poPowHeader) InterlinksProof() (BatchMerkleProof, error) {
 }
 
 func (p *poPowHeader) CheckInterlinksProof() bool {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_popow_header_check_interlinks_proof(p.p)
 	return bool(res)
 }
 
 func (p *poPowHeader) Json() (string, error) {
+	if p.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_popow_header_to_json(p.p, &outStr)
@@ -240,14 +335,30 @@ func (p *poPowHeader) Json() (string, error) {
 }
 
 func (p *poPowHeader) Equals(poPowHeader PoPowHeader) bool {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_po_pow_header_eq(p.p, poPowHeader.pointer())
 	return bool(res)
 }
 
 func (p *poPowHeader) pointer() C.PoPowHeaderPtr {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	return p.p
 }
 
+func (p *poPowHeader) Close() {
+	if p.p != nil {
+		runtime.SetFinalizer(p, nil)
+		finalizePoPowHeader(p)
+		p.p = nil
+	}
+}
+
 func finalizePoPowHeader(p *poPowHeader) {
 	C.ergo_lib_popow_header_delete(p.p)
 }
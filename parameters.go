@@ -10,6 +10,8 @@ import (
 )
 
 type Parameters interface {
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ParametersPtr
 }
 
@@ -74,9 +76,21 @@ func NewParametersFromJson(json string) (Parameters, error) {
 }
 
 func (p *parameters) pointer() C.ParametersPtr {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	return p.p
 }
 
+func (p *parameters) Close() {
+	if p.p != nil {
+		runtime.SetFinalizer(p, nil)
+		finalizeParameters(p)
+		p.p = nil
+	}
+}
+
 func finalizeParameters(p *parameters) {
 	C.ergo_lib_parameters_delete(p.p)
 }
@@ -10,6 +10,8 @@ import "runtime"
 type PreHeader interface {
 	// Equals checks if provided PreHeader is same
 	Equals(preHeader PreHeader) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.PreHeaderPtr
 }
 
@@ -34,14 +36,30 @@ func NewPreHeader(header BlockHeader) PreHeader {
 }
 
 func (h *preHeader) Equals(preHeader PreHeader) bool {
+	if h.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_pre_header_eq(h.p, preHeader.pointer())
 	return bool(res)
 }
 
 func (h *preHeader) pointer() C.PreHeaderPtr {
+	if h.p == nil {
+		panic(ErrClosed)
+	}
+
 	return h.p
 }
 
+func (h *preHeader) Close() {
+	if h.p != nil {
+		runtime.SetFinalizer(h, nil)
+		finalizePreHeader(h)
+		h.p = nil
+	}
+}
+
 func finalizePreHeader(h *preHeader) {
 	C.ergo_lib_preheader_delete(h.p)
 }
@@ -5,7 +5,10 @@ package ergo
 */
 import "C"
 import (
+	"context"
+	"fmt"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -16,14 +19,26 @@ import (
 // in an environment where secrets are known.
 // see EIP-19 for more details -
 // https://github.com/ergoplatform/eips/blob/f280890a4163f2f2e988a0091c078e36912fc531/eip-0019.md
+//
+// ergo-lib-c does not expose the serialization of ReducedTransaction nor the reduced inputs it consists of,
+// so a ReducedTransaction can not be encoded to or decoded from the EIP-19 binary format by this package.
+// The reduction has to be repeated from the UnsignedTransaction where the transaction is signed.
+//
+// For the same reason the sigma propositions the inputs were reduced to, and with them the keys required
+// to sign, are not available. Only for inputs spending a P2PK box the signer is known upfront, it is
+// the Address returned by NewAddressFromTree for the Tree of the spent Box, see P2PKSigners.
 type ReducedTransaction interface {
 	// UnsignedTransaction returns the UnsignedTransaction
 	UnsignedTransaction() UnsignedTransaction
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ReducedTransactionPtr
 }
 
 type reducedTransaction struct {
-	p C.ReducedTransactionPtr
+	p  C.ReducedTransactionPtr
+	mu sync.RWMutex
 }
 
 func newReducedTransaction(r *reducedTransaction) ReducedTransaction {
@@ -34,6 +49,12 @@ func newReducedTransaction(r *reducedTransaction) ReducedTransaction {
 // NewReducedTransaction creates a ReducedTransaction i.e unsigned transaction where each unsigned input
 // is augmented with ReducedInput which contains a script reduction result
 func NewReducedTransaction(unsignedTx UnsignedTransaction, boxesToSpent Boxes, dataBoxes Boxes, stateContext StateContext) (ReducedTransaction, error) {
+	defer readLock(unsignedTx, boxesToSpent, dataBoxes, stateContext)()
+	return reduceTransaction(unsignedTx, boxesToSpent, dataBoxes, stateContext)
+}
+
+// reduceTransaction creates the ReducedTransaction with the arguments read locked by the caller
+func reduceTransaction(unsignedTx UnsignedTransaction, boxesToSpent Boxes, dataBoxes Boxes, stateContext StateContext) (ReducedTransaction, error) {
 	var p C.ReducedTransactionPtr
 
 	errPtr := C.ergo_lib_reduced_tx_from_unsigned_tx(unsignedTx.pointer(), boxesToSpent.pointer(), dataBoxes.pointer(), stateContext.pointer(), &p)
@@ -47,17 +68,104 @@ func NewReducedTransaction(unsignedTx UnsignedTransaction, boxesToSpent Boxes, d
 	return newReducedTransaction(r), nil
 }
 
+// NewReducedTransactionContext creates a ReducedTransaction like NewReducedTransaction, but returns ctx.Err()
+// as soon as ctx is done. The arguments stay in use until the reduction finishes, closing them waits for it
+func NewReducedTransactionContext(ctx context.Context, unsignedTx UnsignedTransaction, boxesToSpent Boxes, dataBoxes Boxes, stateContext StateContext) (ReducedTransaction, error) {
+	return withContext(ctx, []locker{unsignedTx, boxesToSpent, dataBoxes, stateContext}, func() (ReducedTransaction, error) {
+		return reduceTransaction(unsignedTx, boxesToSpent, dataBoxes, stateContext)
+	}, func(r ReducedTransaction) {
+		r.Close()
+	})
+}
+
 func (r *reducedTransaction) UnsignedTransaction() UnsignedTransaction {
+	if r.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.UnsignedTransactionPtr
 	C.ergo_lib_reduced_tx_unsigned_tx(r.p, &p)
 	ut := &unsignedTransaction{p: p}
 	return newUnsignedTransaction(ut)
 }
 
+// P2PKSigners returns the P2PK addresses of the boxes spent by the inputs of reducedTx, each address once in the
+// order of the inputs. boxesToSpend are the boxes the transaction was reduced with. The keys required by inputs
+// spending other boxes are not known, see ReducedTransaction
+func P2PKSigners(reducedTx ReducedTransaction, boxesToSpend Boxes) ([]Address, error) {
+	boxesById := make(map[string]Box)
+	for _, box := range boxesToSpend.All() {
+		boxId := box.BoxId()
+		boxesById[boxId.Base16()] = box
+		boxId.Close()
+	}
+	defer func() {
+		for _, box := range boxesById {
+			box.Close()
+		}
+	}()
+
+	unsignedTx := reducedTx.UnsignedTransaction()
+	defer unsignedTx.Close()
+	unsignedInputs := unsignedTx.UnsignedInputs()
+	defer unsignedInputs.Close()
+
+	var addresses []Address
+	seen := make(map[string]bool)
+	for i, input := range unsignedInputs.All() {
+		boxId := input.BoxId()
+		id := boxId.Base16()
+		boxId.Close()
+		input.Close()
+		box, ok := boxesById[id]
+		if !ok {
+			return nil, fmt.Errorf("box %s spent by input %d is not in boxesToSpend", id, i)
+		}
+
+		tree := box.Tree()
+		address, err := NewAddressFromTree(tree)
+		tree.Close()
+		if err != nil {
+			return nil, err
+		}
+		key := address.Base58(MainnetPrefix)
+		if address.TypePrefix() != P2PkPrefix || seen[key] {
+			address.Close()
+			continue
+		}
+		seen[key] = true
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
 func (r *reducedTransaction) pointer() C.ReducedTransactionPtr {
+	if r.p == nil {
+		panic(ErrClosed)
+	}
+
 	return r.p
 }
 
+func (r *reducedTransaction) rlock() {
+	r.mu.RLock()
+}
+
+func (r *reducedTransaction) runlock() {
+	r.mu.RUnlock()
+}
+
+func (r *reducedTransaction) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.p != nil {
+		runtime.SetFinalizer(r, nil)
+		finalizeReducedTransaction(r)
+		r.p = nil
+	}
+}
+
 func finalizeReducedTransaction(r *reducedTransaction) {
 	C.ergo_lib_reduced_tx_delete(r.p)
 }
@@ -66,11 +174,15 @@ func finalizeReducedTransaction(r *reducedTransaction) {
 type Propositions interface {
 	// Add adds new proposition
 	Add(bytes []byte) error
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.PropositionsPtr
 }
 
 type propositions struct {
-	p C.PropositionsPtr
+	p  C.PropositionsPtr
+	mu sync.RWMutex
 }
 
 func newPropositions(p *propositions) Propositions {
@@ -87,6 +199,13 @@ func NewPropositions() Propositions {
 }
 
 func (p *propositions) Add(bytes []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.p == nil {
+		return ErrClosed
+	}
+
 	byteData := C.CBytes(bytes)
 	defer C.free(unsafe.Pointer(byteData))
 
@@ -99,9 +218,32 @@ func (p *propositions) Add(bytes []byte) error {
 }
 
 func (p *propositions) pointer() C.PropositionsPtr {
+	if p.p == nil {
+		panic(ErrClosed)
+	}
+
 	return p.p
 }
 
+func (p *propositions) rlock() {
+	p.mu.RLock()
+}
+
+func (p *propositions) runlock() {
+	p.mu.RUnlock()
+}
+
+func (p *propositions) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.p != nil {
+		runtime.SetFinalizer(p, nil)
+		finalizePropositions(p)
+		p.p = nil
+	}
+}
+
 func finalizePropositions(p *propositions) {
 	C.ergo_lib_propositions_delete(p.p)
 }
@@ -0,0 +1,45 @@
+package ergo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestP2PKSigners(t *testing.T) {
+	sk := NewSecretKey()
+	p2pkContract, _ := NewContractPayToAddress(sk.Address())
+	multiSigAddress, _ := NewAddress("JryiCXrc7x5D8AhS9DYX1TDzW5C5mT6QyTMQaptF76EQkM15cetxtYKq3u6LymLZLVCyjtgbTKFcfuuX9LLi49Ec5m2p6cwsg5NyEsCQ7na83yEPN")
+	multiSigContract, _ := NewContractPayToAddress(multiSigAddress)
+	testTxId, _ := NewTxId("93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38")
+	inputBoxVal, _ := NewBoxValue(1000000000)
+	unspentBoxes := NewBoxes()
+	for i, contract := range []Contract{p2pkContract, multiSigContract, p2pkContract} {
+		box, _ := NewBox(inputBoxVal, 0, contract, testTxId, uint16(i), NewTokens())
+		unspentBoxes.Add(box)
+	}
+
+	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
+	testContract, _ := NewContractPayToAddress(recipient)
+	outBoxValue, _ := NewBoxValue(3000000000 - SuggestedTxFee().Int64())
+	outbox, _ := NewBoxCandidateBuilder(outBoxValue, testContract, 0).Build()
+	txOutputs := NewBoxCandidates()
+	txOutputs.Add(outbox)
+	targetBalance, _ := SumOfBoxValues(outBoxValue, SuggestedTxFee())
+	testBoxSelection, _ := NewSimpleBoxSelector().Select(unspentBoxes, targetBalance, NewTokens())
+	tx, txErr := NewTxBuilder(testBoxSelection, txOutputs, 0, SuggestedTxFee(), recipient).Build()
+	assert.NoError(t, txErr)
+
+	testBlockHeaders := testBlockHeadersFromJson()
+	testBlockHeader, _ := testBlockHeaders.Get(0)
+	ctx, _ := NewStateContext(NewPreHeader(testBlockHeader), testBlockHeaders, DefaultParameters())
+	reducedTx, reduceErr := NewReducedTransaction(tx, unspentBoxes, NewBoxes(), ctx)
+	assert.NoError(t, reduceErr)
+
+	signers, err := P2PKSigners(reducedTx, unspentBoxes)
+	assert.NoError(t, err)
+	assert.Len(t, signers, 1)
+	assert.Equal(t, sk.Address().Base58(MainnetPrefix), signers[0].Base58(MainnetPrefix))
+
+	_, err = P2PKSigners(reducedTx, NewBoxes())
+	assert.ErrorContains(t, err, "is not in boxesToSpend")
+}
@@ -8,15 +8,28 @@ import (
 	"errors"
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
-// SecretKey represents secret key for the prover
+// SecretKey represents secret key for the prover.
+//
+// ergo-lib-c only exposes dlog secrets, the Diffie-Hellman tuple secrets of proveDHTuple can neither be created
+// nor added to a Wallet through it. Inputs guarded by proveDHTuple, e.g. of mixer or stealth contracts, have to be
+// signed by the Ergo node, whose wallet accepts them as dht secrets of /wallet/transaction/sign.
 type SecretKey interface {
 	// Address returns address of the SecretKey
 	Address() Address
-	// Bytes returns SecretKey encoded to bytes
+	// Bytes returns SecretKey encoded to bytes. The returned slice is a copy owned by the caller,
+	// prefer WithBytes to avoid leaving secret material in Go memory
 	Bytes() []byte
+	// WithBytes lends the SecretKey encoded to bytes to f. The slice is wiped once f returns
+	// and must not be retained
+	WithBytes(f func(bytes []byte) error) error
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once.
+	// The buffers used to exchange key material are wiped by every call, but ergo-lib-c does not zero the
+	// native secret when it is freed
+	Close()
 	pointer() C.SecretKeyPtr
 }
 
@@ -45,7 +58,7 @@ func NewSecretKeyFromBytes(bytes []byte) (SecretKey, error) {
 	}
 
 	byteData := C.CBytes(bytes)
-	defer C.free(unsafe.Pointer(byteData))
+	defer freeSecret(byteData, len(bytes))
 
 	var p C.SecretKeyPtr
 	errPtr := C.ergo_lib_secret_key_from_bytes((*C.uchar)(byteData), &p)
@@ -60,6 +73,10 @@ func NewSecretKeyFromBytes(bytes []byte) (SecretKey, error) {
 }
 
 func (s *secretKey) Address() Address {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.AddressPtr
 	C.ergo_lib_secret_key_get_address(s.p, &p)
 	a := &address{p}
@@ -67,21 +84,57 @@ func (s *secretKey) Address() Address {
 }
 
 func (s *secretKey) Bytes() []byte {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	bytes := C.malloc(C.uintptr_t(32))
 	C.ergo_lib_secret_key_to_bytes(s.p, (*C.uint8_t)(bytes))
-	defer C.free(unsafe.Pointer(bytes))
+	defer freeSecret(bytes, 32)
 	result := C.GoBytes(bytes, C.int(32))
 	return result
 }
 
+func (s *secretKey) WithBytes(f func(bytes []byte) error) error {
+	if s.p == nil {
+		return ErrClosed
+	}
+
+	// the buffer is Go memory so it can still be read, and is wiped, if f retains it
+	bytes := make([]byte, 32)
+	C.ergo_lib_secret_key_to_bytes(s.p, (*C.uint8_t)(unsafe.Pointer(&bytes[0])))
+	defer clear(bytes)
+
+	return f(bytes)
+}
+
 func (s *secretKey) pointer() C.SecretKeyPtr {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	return s.p
 }
 
+func (s *secretKey) Close() {
+	if s.p != nil {
+		runtime.SetFinalizer(s, nil)
+		finalizeSecretKey(s)
+		s.p = nil
+	}
+}
+
 func finalizeSecretKey(s *secretKey) {
 	C.ergo_lib_secret_key_delete(s.p)
 }
 
+// freeSecret overwrites n bytes of C memory at ptr with zeros before releasing it,
+// so secret material does not linger in freed memory
+func freeSecret(ptr unsafe.Pointer, n int) {
+	clear(unsafe.Slice((*byte)(ptr), n))
+	C.free(ptr)
+}
+
 // SecretKeys an ordered collection of SecretKey
 type SecretKeys interface {
 	// Len returns the length of the collection
@@ -92,11 +145,15 @@ type SecretKeys interface {
 	Add(secretKey SecretKey)
 	// All returns an iterator over all SecretKey inside the collection
 	All() iter.Seq2[int, SecretKey]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.SecretKeysPtr
 }
 
 type secretKeys struct {
-	p C.SecretKeysPtr
+	p  C.SecretKeysPtr
+	mu sync.RWMutex
 }
 
 func newSecretKeys(s *secretKeys) SecretKeys {
@@ -113,11 +170,37 @@ func NewSecretKeys() SecretKeys {
 }
 
 func (s *secretKeys) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
+	return s.len()
+}
+
+func (s *secretKeys) len() int {
+	if s.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_secret_keys_len(s.p)
 	return int(res)
 }
 
 func (s *secretKeys) Get(index int) (SecretKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(index)
+}
+
+func (s *secretKeys) get(index int) (SecretKey, error) {
+	if s.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.SecretKeyPtr
 
 	res := C.ergo_lib_secret_keys_get(s.p, C.uintptr_t(index), &p)
@@ -135,27 +218,54 @@ func (s *secretKeys) Get(index int) (SecretKey, error) {
 }
 
 func (s *secretKeys) Add(secretKey SecretKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_secret_keys_add(secretKey.pointer(), s.p)
 }
 
 func (s *secretKeys) All() iter.Seq2[int, SecretKey] {
-	return func(yield func(int, SecretKey) bool) {
-		for i := 0; i < s.Len(); i++ {
-			tk, err := s.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(s, s.len, s.get)
 }
 
 func (s *secretKeys) pointer() C.SecretKeysPtr {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	return s.p
 }
 
+func (s *secretKeys) rlock() {
+	s.mu.RLock()
+}
+
+func (s *secretKeys) runlock() {
+	s.mu.RUnlock()
+}
+
+func (s *secretKeys) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.p != nil {
+		runtime.SetFinalizer(s, nil)
+		finalizeSecretKeys(s)
+		s.p = nil
+	}
+}
+
 func finalizeSecretKeys(s *secretKeys) {
 	C.ergo_lib_secret_keys_delete(s.p)
 }
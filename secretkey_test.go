@@ -12,3 +12,25 @@ func TestSecretKey(t *testing.T) {
 	assert.NoError(t, newKeyErr)
 	assert.Equal(t, key, newKey)
 }
+
+func TestSecretKey_WithBytes(t *testing.T) {
+	key := NewSecretKey()
+	var lent []byte
+	err := key.WithBytes(func(bytes []byte) error {
+		assert.Equal(t, key.Bytes(), bytes)
+		lent = bytes
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, make([]byte, 32), lent)
+}
+
+func TestSecretKey_Close(t *testing.T) {
+	key := NewSecretKey()
+	key.Close()
+	key.Close()
+
+	err := key.WithBytes(func(bytes []byte) error { return nil })
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.PanicsWithValue(t, ErrClosed, func() { key.Bytes() })
+}
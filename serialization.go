@@ -0,0 +1,813 @@
+package ergo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+)
+
+// sigma-rust only exposes the binary format of ErgoTree and Constant. Boxes and transactions are assembled
+// here from the same wire format the node uses, trees and constants are delegated to the native library.
+
+// errUnexpectedEnd is returned when serialized bytes end before the value is complete
+var errUnexpectedEnd = errors.New("unexpected end of serialized bytes")
+
+// errUnmeasured is returned when the length of a serialized value cannot be determined without the native library
+var errUnmeasured = errors.New("length of serialized value is unknown")
+
+// maxBoxSize is the maximum size of a serialized box, no tree or register is larger
+const maxBoxSize = 4096
+
+// type codes of the sigma serialization besides those in sigmaprop.go
+const (
+	booleanTypeCode      = 0x01
+	byteTypeCode         = 0x02
+	shortTypeCode        = 0x03
+	longTypeCode         = 0x05
+	bigIntTypeCode       = 0x06
+	groupElementTypeCode = 0x07
+	collTypeCode         = 0x0c
+	optionTypeCode       = 0x24
+	tupleTypeCode        = 0x60
+	stringTypeCode       = 0x66
+)
+
+type serializedToken struct {
+	id     []byte
+	amount uint64
+}
+
+type serializedCandidate struct {
+	value     uint64
+	tree      []byte
+	height    uint32
+	tokens    []serializedToken
+	registers [][]byte
+}
+
+type serializedExtension struct {
+	key      uint8
+	constant []byte
+}
+
+type serializedInput struct {
+	boxId     []byte
+	proof     []byte
+	extension []serializedExtension
+}
+
+type serializedTransaction struct {
+	inputs     []serializedInput
+	dataInputs [][]byte
+	outputs    []serializedCandidate
+}
+
+func decodeBase16(s string, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(s)
+}
+
+func boxIdBytes(boxId BoxId) ([]byte, error) {
+	defer boxId.Close()
+	return hex.DecodeString(boxId.Base16())
+}
+
+func extensionFields(extension ContextExtension) ([]serializedExtension, error) {
+	defer extension.Close()
+
+	var result []serializedExtension
+	for key := range extension.Keys() {
+		c, err := extension.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		constantBytes, err := c.Bytes()
+		c.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, serializedExtension{key: key, constant: constantBytes})
+	}
+	return result, nil
+}
+
+func candidateFields(candidate BoxCandidate) (serializedCandidate, error) {
+	value := candidate.BoxValue()
+	defer value.Close()
+	ergoTree := candidate.Tree()
+	defer ergoTree.Close()
+
+	treeBytes, err := decodeBase16(ergoTree.Base16())
+	if err != nil {
+		return serializedCandidate{}, err
+	}
+
+	result := serializedCandidate{
+		value:  uint64(value.Int64()),
+		tree:   treeBytes,
+		height: candidate.CreationHeight(),
+	}
+
+	candidateTokens := candidate.Tokens()
+	defer candidateTokens.Close()
+	for _, t := range candidateTokens.All() {
+		tokenId := t.Id()
+		amount := t.Amount()
+		id, err := hex.DecodeString(tokenId.Base16())
+		result.tokens = append(result.tokens, serializedToken{id: id, amount: uint64(amount.Int64())})
+		tokenId.Close()
+		amount.Close()
+		t.Close()
+		if err != nil {
+			return serializedCandidate{}, err
+		}
+	}
+
+	// registers are densely packed starting from R4, the first empty register ends the sequence
+	for registerId := R4; registerId <= R9; registerId++ {
+		c, err := candidate.RegisterValue(registerId)
+		if err != nil {
+			return serializedCandidate{}, err
+		}
+		if c == nil {
+			break
+		}
+		registerBytes, err := c.Bytes()
+		c.Close()
+		if err != nil {
+			return serializedCandidate{}, err
+		}
+		result.registers = append(result.registers, registerBytes)
+	}
+
+	return result, nil
+}
+
+func candidatesFields(candidates BoxCandidates) ([]serializedCandidate, error) {
+	defer candidates.Close()
+
+	var result []serializedCandidate
+	for _, candidate := range candidates.All() {
+		fields, err := candidateFields(candidate)
+		candidate.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, fields)
+	}
+	return result, nil
+}
+
+func dataInputsFields(dataInputs DataInputs) ([][]byte, error) {
+	defer dataInputs.Close()
+
+	var result [][]byte
+	for _, d := range dataInputs.All() {
+		id, err := boxIdBytes(d.BoxId())
+		d.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// appendCandidate appends the serialized candidate to dst. Token ids are replaced by their position
+// in tokenIds when tokenIds is not nil, as done inside of transactions
+func appendCandidate(dst []byte, c serializedCandidate, tokenIds [][]byte) []byte {
+	dst = binary.AppendUvarint(dst, c.value)
+	dst = append(dst, c.tree...)
+	dst = binary.AppendUvarint(dst, uint64(c.height))
+	dst = append(dst, byte(len(c.tokens)))
+	for _, t := range c.tokens {
+		if tokenIds == nil {
+			dst = append(dst, t.id...)
+		} else {
+			dst = binary.AppendUvarint(dst, uint64(tokenIndex(tokenIds, t.id)))
+		}
+		dst = binary.AppendUvarint(dst, t.amount)
+	}
+	dst = append(dst, byte(len(c.registers)))
+	for _, r := range c.registers {
+		dst = append(dst, r...)
+	}
+	return dst
+}
+
+func tokenIndex(tokenIds [][]byte, id []byte) int {
+	for i, tokenId := range tokenIds {
+		if bytes.Equal(tokenId, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (tx serializedTransaction) bytes() []byte {
+	var dst []byte
+
+	dst = binary.AppendUvarint(dst, uint64(len(tx.inputs)))
+	for _, input := range tx.inputs {
+		dst = append(dst, input.boxId...)
+		dst = binary.AppendUvarint(dst, uint64(len(input.proof)))
+		dst = append(dst, input.proof...)
+		dst = append(dst, byte(len(input.extension)))
+		for _, e := range input.extension {
+			dst = append(dst, e.key)
+			dst = append(dst, e.constant...)
+		}
+	}
+
+	dst = binary.AppendUvarint(dst, uint64(len(tx.dataInputs)))
+	for _, d := range tx.dataInputs {
+		dst = append(dst, d...)
+	}
+
+	// distinct token ids of all outputs in order of appearance
+	var tokenIds [][]byte
+	for _, output := range tx.outputs {
+		for _, t := range output.tokens {
+			if tokenIndex(tokenIds, t.id) < 0 {
+				tokenIds = append(tokenIds, t.id)
+			}
+		}
+	}
+	dst = binary.AppendUvarint(dst, uint64(len(tokenIds)))
+	for _, id := range tokenIds {
+		dst = append(dst, id...)
+	}
+
+	dst = binary.AppendUvarint(dst, uint64(len(tx.outputs)))
+	for _, output := range tx.outputs {
+		dst = appendCandidate(dst, output, tokenIds)
+	}
+
+	return dst
+}
+
+type inputJson struct {
+	BoxId     string        `json:"boxId"`
+	Extension extensionJson `json:"extension"`
+}
+
+// extensionJson is a context extension as JSON object, the entries keep their order as it is part of the
+// serialized form
+type extensionJson []serializedExtension
+
+func (e extensionJson) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+	for i, entry := range e {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendQuote(b, strconv.Itoa(int(entry.key)))
+		b = append(b, ':')
+		b = strconv.AppendQuote(b, hex.EncodeToString(entry.constant))
+	}
+	return append(b, '}'), nil
+}
+
+type dataInputJson struct {
+	BoxId string `json:"boxId"`
+}
+
+type tokenJson struct {
+	TokenId string `json:"tokenId"`
+	Amount  uint64 `json:"amount"`
+}
+
+type outputJson struct {
+	Value               uint64            `json:"value"`
+	ErgoTree            string            `json:"ergoTree"`
+	CreationHeight      uint32            `json:"creationHeight"`
+	Assets              []tokenJson       `json:"assets"`
+	AdditionalRegisters map[string]string `json:"additionalRegisters"`
+}
+
+type unsignedTransactionJson struct {
+	Inputs     []inputJson     `json:"inputs"`
+	DataInputs []dataInputJson `json:"dataInputs"`
+	Outputs    []outputJson    `json:"outputs"`
+}
+
+// unsignedJson returns the transaction as JSON accepted by NewUnsignedTransactionFromJson, proofs are dropped
+func (tx serializedTransaction) unsignedJson() (string, error) {
+	result := unsignedTransactionJson{
+		Inputs:     make([]inputJson, 0, len(tx.inputs)),
+		DataInputs: make([]dataInputJson, 0, len(tx.dataInputs)),
+		Outputs:    make([]outputJson, 0, len(tx.outputs)),
+	}
+	for _, input := range tx.inputs {
+		result.Inputs = append(result.Inputs, inputJson{BoxId: hex.EncodeToString(input.boxId), Extension: input.extension})
+	}
+	for _, d := range tx.dataInputs {
+		result.DataInputs = append(result.DataInputs, dataInputJson{BoxId: hex.EncodeToString(d)})
+	}
+	for _, output := range tx.outputs {
+		o := outputJson{
+			Value:               output.value,
+			ErgoTree:            hex.EncodeToString(output.tree),
+			CreationHeight:      output.height,
+			Assets:              make([]tokenJson, 0, len(output.tokens)),
+			AdditionalRegisters: make(map[string]string, len(output.registers)),
+		}
+		for _, t := range output.tokens {
+			o.Assets = append(o.Assets, tokenJson{TokenId: hex.EncodeToString(t.id), Amount: t.amount})
+		}
+		for j, r := range output.registers {
+			o.AdditionalRegisters["R"+strconv.Itoa(j+int(R4))] = hex.EncodeToString(r)
+		}
+		result.Outputs = append(result.Outputs, o)
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// sigmaReader reads values of the sigma serialization format
+type sigmaReader struct {
+	bytes []byte
+	pos   int
+}
+
+func (r *sigmaReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.bytes[r.pos:])
+	if n <= 0 {
+		return 0, errUnexpectedEnd
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *sigmaReader) take(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.bytes) {
+		return nil, errUnexpectedEnd
+	}
+	v := r.bytes[r.pos : r.pos+n]
+	r.pos += n
+	return v, nil
+}
+
+func (r *sigmaReader) byte() (byte, error) {
+	v, err := r.take(1)
+	if err != nil {
+		return 0, err
+	}
+	return v[0], nil
+}
+
+// tree reads a serialized ErgoTree. Trees with the size flag in the header carry their length,
+// other trees are parsed by the native library from the bytes returned by window and measured by serializing
+// them again
+func (r *sigmaReader) tree() ([]byte, error) {
+	const sizeFlag = 0x08
+
+	if r.pos >= len(r.bytes) {
+		return nil, errUnexpectedEnd
+	}
+	if r.bytes[r.pos]&sizeFlag != 0 {
+		size, n := binary.Uvarint(r.bytes[r.pos+1:])
+		if n <= 0 {
+			return nil, errUnexpectedEnd
+		}
+		return r.take(1 + n + int(size))
+	}
+
+	t, err := NewTree(hex.EncodeToString(r.window((*sigmaReader).skipTree)))
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	treeBytes, err := decodeBase16(t.Base16())
+	if err != nil {
+		return nil, err
+	}
+	return r.takeExpected(treeBytes, "tree")
+}
+
+// constant reads a serialized Constant, it is parsed by the native library from the bytes returned by window and
+// measured by serializing it again
+func (r *sigmaReader) constant() ([]byte, error) {
+	c, err := NewConstantFromBytes(r.window((*sigmaReader).skipConstant))
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	constantBytes, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return r.takeExpected(constantBytes, "constant")
+}
+
+// takeExpected takes the bytes of a value measured by serializing it again. A value that is not in its
+// canonical form serializes differently and is rejected, as its length would be wrong
+func (r *sigmaReader) takeExpected(expected []byte, name string) ([]byte, error) {
+	v, err := r.take(len(expected))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(v, expected) {
+		return nil, errors.New("serialized " + name + " is not in canonical form")
+	}
+	return v, nil
+}
+
+// window returns the bytes from the current position to the end of the value skipped by skip, so the native
+// library does not parse the rest of the serialized bytes for every value. If skip cannot determine the end,
+// the bytes are cut at maxBoxSize
+func (r *sigmaReader) window(skip func(*sigmaReader) error) []byte {
+	m := &sigmaReader{bytes: r.bytes, pos: r.pos}
+	if err := skip(m); err == nil {
+		return r.bytes[r.pos:m.pos]
+	}
+	return r.bytes[r.pos:min(len(r.bytes), r.pos+maxBoxSize)]
+}
+
+// skipTree skips an ErgoTree without the size flag. Only trees whose root is a constant can be skipped,
+// e.g. the trees of P2PK addresses
+func (r *sigmaReader) skipTree() error {
+	const constantSegregationFlag = 0x10
+
+	header, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if header&constantSegregationFlag != 0 {
+		count, err := r.uvarint()
+		if err != nil {
+			return err
+		}
+		if count > uint64(len(r.bytes)-r.pos) {
+			return errUnexpectedEnd
+		}
+		for range count {
+			if err := r.skipConstant(); err != nil {
+				return err
+			}
+		}
+	}
+	return r.skipConstant()
+}
+
+// skipConstant skips a Constant, i.e. its type followed by its value
+func (r *sigmaReader) skipConstant() error {
+	t, err := r.sigmaType()
+	if err != nil {
+		return err
+	}
+	return r.skipValue(t)
+}
+
+// sigmaType is a type of the sigma serialization format
+type sigmaType struct {
+	// code is the code of a primitive or predefined type, collTypeCode, optionTypeCode or tupleTypeCode
+	code byte
+	// elems is the element type of a Coll or Option or the item types of a tuple
+	elems []sigmaType
+}
+
+// sigmaType reads a serialized type. Types below tupleTypeCode combine a constructor with a primitive type
+// in one code, a primitive of 0 means the argument type follows
+func (r *sigmaReader) sigmaType() (sigmaType, error) {
+	const primRange = 12
+
+	c, err := r.byte()
+	if err != nil {
+		return sigmaType{}, err
+	}
+	if c == 0 {
+		return sigmaType{}, errors.New("invalid type code 0")
+	}
+	if c == tupleTypeCode {
+		n, err := r.byte()
+		if err != nil {
+			return sigmaType{}, err
+		}
+		return r.tupleType(int(n))
+	}
+	if c > tupleTypeCode {
+		return sigmaType{code: c}, nil
+	}
+
+	prim := sigmaType{code: c % primRange}
+	arg := func() (sigmaType, error) {
+		if prim.code == 0 {
+			return r.sigmaType()
+		}
+		return prim, nil
+	}
+	coll := func(t sigmaType) sigmaType { return sigmaType{code: collTypeCode, elems: []sigmaType{t}} }
+	option := func(t sigmaType) sigmaType { return sigmaType{code: optionTypeCode, elems: []sigmaType{t}} }
+
+	switch c / primRange {
+	case 0:
+		return prim, nil
+	case 1:
+		elem, err := arg()
+		return coll(elem), err
+	case 2:
+		elem, err := arg()
+		return coll(coll(elem)), err
+	case 3:
+		elem, err := arg()
+		return option(elem), err
+	case 4:
+		elem, err := arg()
+		return option(coll(elem)), err
+	case 5:
+		// (prim, T) or (T1, T2)
+		if prim.code == 0 {
+			return r.tupleType(2)
+		}
+		second, err := r.sigmaType()
+		return sigmaType{code: tupleTypeCode, elems: []sigmaType{prim, second}}, err
+	case 6:
+		// (T, prim) or (T1, T2, T3)
+		if prim.code == 0 {
+			return r.tupleType(3)
+		}
+		first, err := r.sigmaType()
+		return sigmaType{code: tupleTypeCode, elems: []sigmaType{first, prim}}, err
+	default:
+		// (prim, prim) or (T1, T2, T3, T4)
+		if prim.code == 0 {
+			return r.tupleType(4)
+		}
+		return sigmaType{code: tupleTypeCode, elems: []sigmaType{prim, prim}}, nil
+	}
+}
+
+func (r *sigmaReader) tupleType(n int) (sigmaType, error) {
+	t := sigmaType{code: tupleTypeCode}
+	for range n {
+		item, err := r.sigmaType()
+		if err != nil {
+			return t, err
+		}
+		t.elems = append(t.elems, item)
+	}
+	return t, nil
+}
+
+// skipValue skips a value of type t. Every value it can skip takes at least one byte, which bounds the
+// work for collections by the number of bytes left. Values of other types return errUnmeasured
+func (r *sigmaReader) skipValue(t sigmaType) error {
+	var err error
+	switch t.code {
+	case booleanTypeCode, byteTypeCode:
+		_, err = r.take(1)
+	case shortTypeCode, intTypeCode, longTypeCode:
+		_, err = r.uvarint()
+	case bigIntTypeCode, stringTypeCode:
+		err = r.skipBytes()
+	case groupElementTypeCode:
+		_, err = r.take(groupElementLength)
+	case sigmaPropTypeCode:
+		err = r.skipSigmaBoolean()
+	case collTypeCode:
+		err = r.skipColl(t.elems[0])
+	case optionTypeCode:
+		var flag byte
+		if flag, err = r.byte(); err == nil && flag != 0 {
+			err = r.skipValue(t.elems[0])
+		}
+	case tupleTypeCode:
+		if len(t.elems) < 2 {
+			return errUnmeasured
+		}
+		for _, item := range t.elems {
+			if err = r.skipValue(item); err != nil {
+				break
+			}
+		}
+	default:
+		return errUnmeasured
+	}
+	return err
+}
+
+// skipBytes skips bytes preceded by their length
+func (r *sigmaReader) skipBytes() error {
+	n, err := r.uvarint()
+	if err != nil {
+		return err
+	}
+	if n > uint64(len(r.bytes)-r.pos) {
+		return errUnexpectedEnd
+	}
+	_, err = r.take(int(n))
+	return err
+}
+
+// skipColl skips a collection, collections of booleans are packed into bits
+func (r *sigmaReader) skipColl(elem sigmaType) error {
+	n, err := r.uvarint()
+	if err != nil {
+		return err
+	}
+	if elem.code == booleanTypeCode {
+		n = (n + 7) / 8
+	}
+	if n > uint64(len(r.bytes)-r.pos) {
+		return errUnexpectedEnd
+	}
+	if elem.code == booleanTypeCode || elem.code == byteTypeCode {
+		_, err = r.take(int(n))
+		return err
+	}
+	for range n {
+		if err := r.skipValue(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipSigmaBoolean skips a sigma proposition in the format of SigmaProp.appendSigmaBoolean, trivial propositions
+// return errUnmeasured
+func (r *sigmaReader) skipSigmaBoolean() error {
+	code, err := r.byte()
+	if err != nil {
+		return err
+	}
+	switch code {
+	case proveDlogCode:
+		_, err = r.take(groupElementLength)
+		return err
+	case proveDHTupleCode:
+		_, err = r.take(4 * groupElementLength)
+		return err
+	case andCode, orCode, atLeastCode:
+	default:
+		return errUnmeasured
+	}
+
+	if code == atLeastCode {
+		if _, err := r.uvarint(); err != nil {
+			return err
+		}
+	}
+	n, err := r.uvarint()
+	if err != nil {
+		return err
+	}
+	if n > uint64(len(r.bytes)-r.pos) {
+		return errUnexpectedEnd
+	}
+	for range n {
+		if err := r.skipSigmaBoolean(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sigmaReader) candidate(tokenIds [][]byte) (serializedCandidate, error) {
+	var c serializedCandidate
+	var err error
+
+	if c.value, err = r.uvarint(); err != nil {
+		return c, err
+	}
+	if c.tree, err = r.tree(); err != nil {
+		return c, err
+	}
+	height, err := r.uvarint()
+	if err != nil {
+		return c, err
+	}
+	if height > math.MaxUint32 {
+		return c, errors.New("creation height " + strconv.FormatUint(height, 10) + " out of range")
+	}
+	c.height = uint32(height)
+
+	tokensCount, err := r.byte()
+	if err != nil {
+		return c, err
+	}
+	for range tokensCount {
+		index, err := r.uvarint()
+		if err != nil {
+			return c, err
+		}
+		if index >= uint64(len(tokenIds)) {
+			return c, errors.New("token index " + strconv.FormatUint(index, 10) + " out of range")
+		}
+		amount, err := r.uvarint()
+		if err != nil {
+			return c, err
+		}
+		c.tokens = append(c.tokens, serializedToken{id: tokenIds[index], amount: amount})
+	}
+
+	registersCount, err := r.byte()
+	if err != nil {
+		return c, err
+	}
+	if int(registersCount) > int(R9-R4+1) {
+		return c, errors.New("too many registers")
+	}
+	for range registersCount {
+		register, err := r.constant()
+		if err != nil {
+			return c, err
+		}
+		c.registers = append(c.registers, register)
+	}
+
+	return c, nil
+}
+
+func parseTransaction(b []byte) (serializedTransaction, error) {
+	const idLength = 32
+
+	r := &sigmaReader{bytes: b}
+	var tx serializedTransaction
+
+	inputsCount, err := r.uvarint()
+	if err != nil {
+		return tx, err
+	}
+	for range inputsCount {
+		var input serializedInput
+		if input.boxId, err = r.take(idLength); err != nil {
+			return tx, err
+		}
+		proofLength, err := r.uvarint()
+		if err != nil {
+			return tx, err
+		}
+		if input.proof, err = r.take(int(proofLength)); err != nil {
+			return tx, err
+		}
+		extensionCount, err := r.byte()
+		if err != nil {
+			return tx, err
+		}
+		for range extensionCount {
+			var e serializedExtension
+			if e.key, err = r.byte(); err != nil {
+				return tx, err
+			}
+			if e.constant, err = r.constant(); err != nil {
+				return tx, err
+			}
+			input.extension = append(input.extension, e)
+		}
+		tx.inputs = append(tx.inputs, input)
+	}
+
+	dataInputsCount, err := r.uvarint()
+	if err != nil {
+		return tx, err
+	}
+	for range dataInputsCount {
+		id, err := r.take(idLength)
+		if err != nil {
+			return tx, err
+		}
+		tx.dataInputs = append(tx.dataInputs, id)
+	}
+
+	tokensCount, err := r.uvarint()
+	if err != nil {
+		return tx, err
+	}
+	var tokenIds [][]byte
+	for range tokensCount {
+		id, err := r.take(idLength)
+		if err != nil {
+			return tx, err
+		}
+		tokenIds = append(tokenIds, id)
+	}
+
+	outputsCount, err := r.uvarint()
+	if err != nil {
+		return tx, err
+	}
+	for range outputsCount {
+		output, err := r.candidate(tokenIds)
+		if err != nil {
+			return tx, err
+		}
+		tx.outputs = append(tx.outputs, output)
+	}
+
+	if r.pos != len(b) {
+		return tx, errors.New("transaction bytes contain trailing data")
+	}
+
+	return tx, nil
+}
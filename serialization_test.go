@@ -0,0 +1,46 @@
+package ergo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSigmaReader_Window(t *testing.T) {
+	publicKey := "03" + hex.EncodeToString(bytes.Repeat([]byte{0x11}, 32))
+	trailing := bytes.Repeat([]byte{0xff}, 2*maxBoxSize)
+
+	tests := []struct {
+		name  string
+		value string
+		skip  func(*sigmaReader) error
+	}{
+		{"p2pk tree", "0008cd" + publicKey, (*sigmaReader).skipTree},
+		{"segregated tree", "1001040208cd" + publicKey, (*sigmaReader).skipTree},
+		{"int", "0408", (*sigmaReader).skipConstant},
+		{"coll of bytes", "0e03010203", (*sigmaReader).skipConstant},
+		{"coll of booleans", "0d09ff01", (*sigmaReader).skipConstant},
+		{"coll of coll of longs", "1d020105020406", (*sigmaReader).skipConstant},
+		{"pair of int and long", "40050204", (*sigmaReader).skipConstant},
+		{"tuple", "60030402050e0101", (*sigmaReader).skipConstant},
+		{"option", "280104", (*sigmaReader).skipConstant},
+		{"threshold", "08980102cd" + publicKey + "cd" + publicKey, (*sigmaReader).skipConstant},
+	}
+	for _, test := range tests {
+		value, err := hex.DecodeString(test.value)
+		assert.NoError(t, err)
+		r := &sigmaReader{bytes: append(value, trailing...)}
+		assert.Equal(t, value, r.window(test.skip), test.name)
+	}
+
+	// the root of the tree is not a constant
+	r := &sigmaReader{bytes: append([]byte{0x10, 0x01, 0x04, 0x02, 0xd1, 0x91, 0xa3}, trailing...)}
+	assert.Len(t, r.window((*sigmaReader).skipTree), maxBoxSize)
+	// a Box constant
+	r = &sigmaReader{bytes: append([]byte{0x63}, trailing...)}
+	assert.Len(t, r.window((*sigmaReader).skipConstant), maxBoxSize)
+	// the collection is longer than the bytes
+	r = &sigmaReader{bytes: []byte{0x0e, 0x05, 0x01}}
+	assert.Len(t, r.window((*sigmaReader).skipConstant), 3)
+}
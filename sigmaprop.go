@@ -0,0 +1,251 @@
+package ergo
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+// op codes and type codes of the ErgoTree serialization used by SigmaProp
+const (
+	sigmaPropTypeCode    = 0x08
+	intTypeCode          = 0x04
+	proveDlogCode        = 0xcd
+	proveDHTupleCode     = 0xce
+	andCode              = 0x96
+	orCode               = 0x97
+	atLeastCode          = 0x98
+	geCode               = 0x92
+	heightCode           = 0xa3
+	concreteCollection   = 0x83
+	boolToSigmaPropCode  = 0xd1
+	sigmaAndCode         = 0xea
+	sigmaOrCode          = 0xeb
+	groupElementLength   = 33
+	maxSigmaPropChildren = 255
+)
+
+// SigmaProp is a sigma proposition that can be turned into a Tree with NewTreeFromSigmaProp without the ErgoScript
+// compiler. The same SigmaProp always results in the same Tree bytes
+type SigmaProp interface {
+	// isStatic reports whether the proposition is a SigmaBoolean, i.e. does not depend on the spending context
+	isStatic() bool
+	// appendSigmaBoolean appends the SigmaBoolean serialization, only valid if isStatic
+	appendSigmaBoolean(dst []byte) []byte
+	// appendExpr appends the proposition as ErgoTree expression
+	appendExpr(dst []byte) []byte
+}
+
+type proveDlog struct {
+	publicKey []byte
+}
+
+type proveDHTuple struct {
+	g, h, u, v []byte
+}
+
+type sigmaConjecture struct {
+	// code is andCode, orCode or atLeastCode
+	code  byte
+	k     int
+	props []SigmaProp
+}
+
+type heightLock struct {
+	minHeight int32
+	prop      SigmaProp
+}
+
+// NewProveDlog creates a SigmaProp that requires knowledge of the secret key of publicKey,
+// a compressed group element of 33 bytes
+func NewProveDlog(publicKey []byte) (SigmaProp, error) {
+	if err := checkGroupElement(publicKey); err != nil {
+		return nil, err
+	}
+	return proveDlog{publicKey: publicKey}, nil
+}
+
+// NewProveDlogFromAddress creates the SigmaProp guarding a P2PK address
+func NewProveDlogFromAddress(address Address) (SigmaProp, error) {
+	proposition, err := p2pkProposition(address)
+	if err != nil {
+		return nil, err
+	}
+	return NewProveDlog(proposition[1:])
+}
+
+// NewProveDHTuple creates a SigmaProp that requires knowledge of x with u = g^x and v = h^x,
+// all group elements are compressed and 33 bytes long
+func NewProveDHTuple(g []byte, h []byte, u []byte, v []byte) (SigmaProp, error) {
+	for _, e := range [][]byte{g, h, u, v} {
+		if err := checkGroupElement(e); err != nil {
+			return nil, err
+		}
+	}
+	return proveDHTuple{g: g, h: h, u: u, v: v}, nil
+}
+
+// NewSigmaAnd creates a SigmaProp that requires all props to be proven
+func NewSigmaAnd(props ...SigmaProp) (SigmaProp, error) {
+	if err := checkChildren(props); err != nil {
+		return nil, err
+	}
+	return sigmaConjecture{code: andCode, props: props}, nil
+}
+
+// NewSigmaOr creates a SigmaProp that requires one of props to be proven
+func NewSigmaOr(props ...SigmaProp) (SigmaProp, error) {
+	if err := checkChildren(props); err != nil {
+		return nil, err
+	}
+	return sigmaConjecture{code: orCode, props: props}, nil
+}
+
+// NewAtLeast creates a SigmaProp that requires k of props to be proven, e.g. a 2-of-3 multisig
+func NewAtLeast(k int, props ...SigmaProp) (SigmaProp, error) {
+	if err := checkChildren(props); err != nil {
+		return nil, err
+	}
+	if k < 1 || k > len(props) {
+		return nil, errors.New("threshold must be between 1 and the number of propositions")
+	}
+	return sigmaConjecture{code: atLeastCode, k: k, props: props}, nil
+}
+
+// NewHeightLock creates a SigmaProp that requires prop to be proven and can not be spent before minHeight
+func NewHeightLock(minHeight int32, prop SigmaProp) (SigmaProp, error) {
+	if prop == nil {
+		return nil, errors.New("height lock needs a proposition")
+	}
+	return heightLock{minHeight: minHeight, prop: prop}, nil
+}
+
+// NewTreeFromSigmaProp creates the Tree guarded by prop
+func NewTreeFromSigmaProp(prop SigmaProp) (Tree, error) {
+	// version 0 header without constant segregation, the root is serialized inline
+	treeBytes := prop.appendExpr([]byte{0x00})
+	return NewTree(hex.EncodeToString(treeBytes))
+}
+
+// NewContractFromSigmaProp creates the Contract guarded by prop
+func NewContractFromSigmaProp(prop SigmaProp) (Contract, error) {
+	ergoTree, err := NewTreeFromSigmaProp(prop)
+	if err != nil {
+		return nil, err
+	}
+	defer ergoTree.Close()
+	return NewContractFromTree(ergoTree), nil
+}
+
+func checkGroupElement(e []byte) error {
+	if len(e) != groupElementLength || (e[0] != 0x02 && e[0] != 0x03) {
+		return errors.New("group element must be a compressed point of 33 bytes")
+	}
+	return nil
+}
+
+func checkChildren(props []SigmaProp) error {
+	if len(props) < 2 || len(props) > maxSigmaPropChildren {
+		return errors.New("sigma conjecture needs between 2 and 255 propositions")
+	}
+	for _, p := range props {
+		if p == nil {
+			return errors.New("sigma conjecture contains nil proposition")
+		}
+	}
+	return nil
+}
+
+func (p proveDlog) isStatic() bool {
+	return true
+}
+
+func (p proveDlog) appendSigmaBoolean(dst []byte) []byte {
+	return append(append(dst, proveDlogCode), p.publicKey...)
+}
+
+func (p proveDlog) appendExpr(dst []byte) []byte {
+	return p.appendSigmaBoolean(append(dst, sigmaPropTypeCode))
+}
+
+func (p proveDHTuple) isStatic() bool {
+	return true
+}
+
+func (p proveDHTuple) appendSigmaBoolean(dst []byte) []byte {
+	dst = append(dst, proveDHTupleCode)
+	for _, e := range [][]byte{p.g, p.h, p.u, p.v} {
+		dst = append(dst, e...)
+	}
+	return dst
+}
+
+func (p proveDHTuple) appendExpr(dst []byte) []byte {
+	return p.appendSigmaBoolean(append(dst, sigmaPropTypeCode))
+}
+
+func (c sigmaConjecture) isStatic() bool {
+	for _, p := range c.props {
+		if !p.isStatic() {
+			return false
+		}
+	}
+	return true
+}
+
+func (c sigmaConjecture) appendSigmaBoolean(dst []byte) []byte {
+	dst = append(dst, c.code)
+	if c.code == atLeastCode {
+		dst = binary.AppendUvarint(dst, uint64(c.k))
+	}
+	dst = binary.AppendUvarint(dst, uint64(len(c.props)))
+	for _, p := range c.props {
+		dst = p.appendSigmaBoolean(dst)
+	}
+	return dst
+}
+
+func (c sigmaConjecture) appendExpr(dst []byte) []byte {
+	// static conjectures are a single constant, like the trees of P2PK addresses
+	if c.isStatic() {
+		return c.appendSigmaBoolean(append(dst, sigmaPropTypeCode))
+	}
+
+	switch c.code {
+	case andCode, orCode:
+		op := byte(sigmaAndCode)
+		if c.code == orCode {
+			op = sigmaOrCode
+		}
+		dst = binary.AppendUvarint(append(dst, op), uint64(len(c.props)))
+	default:
+		// AtLeast(bound: Int, input: Coll[SigmaProp])
+		dst = appendIntConstant(append(dst, atLeastCode), int32(c.k))
+		dst = binary.AppendUvarint(append(dst, concreteCollection), uint64(len(c.props)))
+		dst = append(dst, sigmaPropTypeCode)
+	}
+	for _, p := range c.props {
+		dst = p.appendExpr(dst)
+	}
+	return dst
+}
+
+func (l heightLock) isStatic() bool {
+	return false
+}
+
+func (l heightLock) appendSigmaBoolean(dst []byte) []byte {
+	panic("height lock is not a SigmaBoolean")
+}
+
+func (l heightLock) appendExpr(dst []byte) []byte {
+	// sigmaProp(HEIGHT >= minHeight) && prop
+	dst = append(dst, sigmaAndCode, 2, boolToSigmaPropCode, geCode, heightCode)
+	dst = appendIntConstant(dst, l.minHeight)
+	return l.prop.appendExpr(dst)
+}
+
+func appendIntConstant(dst []byte, v int32) []byte {
+	// Int values are ZigZag encoded before the VLQ encoding
+	return binary.AppendUvarint(append(dst, intTypeCode), uint64(uint32((v<<1)^(v>>31))))
+}
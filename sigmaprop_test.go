@@ -0,0 +1,71 @@
+package ergo
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNewTreeFromSigmaProp_ProveDlog(t *testing.T) {
+	addr, _ := NewAddress("9hdxkYakTHWXR992umPcvh8bAEGG9Sdoi7uW8TKXk1enXCDFBVJ")
+
+	prop, err := NewProveDlogFromAddress(addr)
+	assert.NoError(t, err)
+
+	tree, err := NewTreeFromSigmaProp(prop)
+	assert.NoError(t, err)
+	assert.True(t, addr.Tree().Equals(tree))
+}
+
+func TestNewTreeFromSigmaProp_AtLeast(t *testing.T) {
+	var pks []SigmaProp
+	var pkHex []string
+	for range 3 {
+		addr := NewSecretKey().Address()
+		pk, _ := NewProveDlogFromAddress(addr)
+		pks = append(pks, pk)
+		b16, _ := addr.Tree().Base16()
+		pkHex = append(pkHex, strings.TrimPrefix(b16, "0008"))
+	}
+
+	prop, err := NewAtLeast(2, pks...)
+	assert.NoError(t, err)
+
+	tree, err := NewTreeFromSigmaProp(prop)
+	assert.NoError(t, err)
+	b16, _ := tree.Base16()
+	assert.Equal(t, "0008980203"+strings.Join(pkHex, ""), b16)
+
+	contract, err := NewContractFromSigmaProp(prop)
+	assert.NoError(t, err)
+	assert.True(t, contract.Tree().Equals(tree))
+
+	_, err = NewAtLeast(4, pks...)
+	assert.Error(t, err)
+	_, err = NewSigmaAnd(pks[0])
+	assert.Error(t, err)
+}
+
+func TestNewTreeFromSigmaProp_HeightLock(t *testing.T) {
+	pk, _ := NewProveDlogFromAddress(NewSecretKey().Address())
+	dhtElement, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	dht, err := NewProveDHTuple(dhtElement, dhtElement, dhtElement, dhtElement)
+	assert.NoError(t, err)
+
+	or, _ := NewSigmaOr(pk, dht)
+	heightLock, err := NewHeightLock(1000000, pk)
+	assert.NoError(t, err)
+	prop, _ := NewSigmaAnd(heightLock, or)
+
+	tree, err := NewTreeFromSigmaProp(prop)
+	assert.NoError(t, err)
+	b16, _ := tree.Base16()
+	// SigmaAnd(BoolToSigmaProp(HEIGHT >= 1000000) && pk, pk || dht)
+	assert.True(t, strings.HasPrefix(b16, "00ea02ea02d192a30480897a08cd"))
+
+	_, err = NewProveDHTuple(dhtElement, dhtElement, dhtElement, dhtElement[1:])
+	assert.Error(t, err)
+	_, err = NewHeightLock(1000000, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package ergo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrNotSupported is returned by a Signer for an operation it does not support
+var ErrNotSupported = errors.New("ergo: operation not supported by the signer")
+
+// maxRemoteSignSize limits the bodies of the remote signer protocol
+const maxRemoteSignSize = 1 << 22
+
+// Signer signs transactions and messages. Wallet is the in-process implementation, RemoteSigner forwards the
+// signing to another process, e.g. a signing service or a signer backed by an HSM
+type Signer interface {
+	// SignTransaction signs unsignedTx spending boxesToSpend
+	SignTransaction(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error)
+	// SignReducedTransaction signs reducedTx
+	SignReducedTransaction(reducedTx ReducedTransaction) (Transaction, error)
+	// GenerateCommitments generates the commitments of the signer for a multi signature transaction
+	GenerateCommitments(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (TransactionHintsBag, error)
+	// SignMessageUsingP2PK signs message with the secret key of the P2PK address
+	SignMessageUsingP2PK(address Address, message []byte) (SignedMessage, error)
+}
+
+var (
+	_ Signer = Wallet(nil)
+	_ Signer = (*RemoteSigner)(nil)
+)
+
+// RemoteSignRequest is the request of the remote signer protocol, it is POSTed as JSON. The unsigned transaction
+// and the boxes are in the format of UnsignedTransaction.Json and Box.Json
+type RemoteSignRequest struct {
+	UnsignedTx json.RawMessage   `json:"unsignedTx"`
+	Inputs     []json.RawMessage `json:"inputs"`
+	DataInputs []json.RawMessage `json:"dataInputs"`
+}
+
+// RemoteSignResponse is the response of the remote signer protocol, either the signed transaction in the format
+// of Transaction.Json or the reason the transaction was not signed
+type RemoteSignResponse struct {
+	Tx    json.RawMessage `json:"tx,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// NewRemoteSignRequest creates the RemoteSignRequest to sign unsignedTx spending boxesToSpend
+func NewRemoteSignRequest(unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (*RemoteSignRequest, error) {
+	txJson, err := unsignedTx.Json()
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := boxesJson(boxesToSpend)
+	if err != nil {
+		return nil, err
+	}
+	dataInputs, err := boxesJson(dataBoxes)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteSignRequest{UnsignedTx: json.RawMessage(txJson), Inputs: inputs, DataInputs: dataInputs}, nil
+}
+
+// Decode returns the unsigned transaction, the boxes to spend and the data boxes of the request
+func (r *RemoteSignRequest) Decode() (UnsignedTransaction, Boxes, Boxes, error) {
+	unsignedTx, err := NewUnsignedTransactionFromJson(string(r.UnsignedTx))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	boxesToSpend, err := boxesFromJson(r.Inputs)
+	if err != nil {
+		unsignedTx.Close()
+		return nil, nil, nil, err
+	}
+	dataBoxes, err := boxesFromJson(r.DataInputs)
+	if err != nil {
+		unsignedTx.Close()
+		boxesToSpend.Close()
+		return nil, nil, nil, err
+	}
+	return unsignedTx, boxesToSpend, dataBoxes, nil
+}
+
+// RemoteSigner is a Signer that forwards SignTransaction to a signer in another process. It POSTs a
+// RemoteSignRequest to URL and expects a RemoteSignResponse, NewRemoteSignerHandler serves this protocol.
+// ergo-lib-c cannot serialize StateContext, ReducedTransaction, hints and SignedMessage, therefore:
+//   - the remote signer signs with its own StateContext, the one passed to SignTransaction is not sent
+//   - SignReducedTransaction, GenerateCommitments and SignMessageUsingP2PK return ErrNotSupported
+type RemoteSigner struct {
+	// URL is the endpoint of the remote signer
+	URL string
+	// Client is used for the requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// NewRemoteSigner creates a RemoteSigner for the endpoint at url
+func NewRemoteSigner(url string) *RemoteSigner {
+	return &RemoteSigner{URL: url}
+}
+
+func (s *RemoteSigner) SignTransaction(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error) {
+	return s.SignTransactionContext(context.Background(), stateContext, unsignedTx, boxesToSpend, dataBoxes)
+}
+
+// SignTransactionContext is like SignTransaction but aborts the request once ctx is done
+func (s *RemoteSigner) SignTransactionContext(ctx context.Context, stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error) {
+	request, err := NewRemoteSignRequest(unsignedTx, boxesToSpend, dataBoxes)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	var response RemoteSignResponse
+	if err := json.NewDecoder(io.LimitReader(httpResponse.Body, maxRemoteSignSize)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("remote signer: %s: %w", httpResponse.Status, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("remote signer: %s", response.Error)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer: %s", httpResponse.Status)
+	}
+
+	tx, err := NewTransactionFromJson(string(response.Tx))
+	if err != nil {
+		return nil, err
+	}
+	// the remote signer must not sign anything else than what was asked for
+	txId := tx.TxId()
+	defer txId.Close()
+	unsignedTxId := unsignedTx.TxId()
+	defer unsignedTxId.Close()
+	if !txId.Equals(unsignedTxId) {
+		tx.Close()
+		return nil, errors.New("remote signer: signed transaction differs from the unsigned transaction")
+	}
+	return tx, nil
+}
+
+func (s *RemoteSigner) SignReducedTransaction(reducedTx ReducedTransaction) (Transaction, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *RemoteSigner) GenerateCommitments(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (TransactionHintsBag, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *RemoteSigner) SignMessageUsingP2PK(address Address, message []byte) (SignedMessage, error) {
+	return nil, ErrNotSupported
+}
+
+// NewRemoteSignerHandler returns an http.Handler serving the protocol of RemoteSigner with signer, e.g. a Wallet.
+// newStateContext returns the StateContext to sign a request with, usually built from the last block headers of
+// a node. The handler never closes the returned StateContext, it stays owned by the caller, so newStateContext
+// may return the same StateContext for many requests. One created per request is freed by the garbage collector
+func NewRemoteSignerHandler(signer Signer, newStateContext func() (StateContext, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeRemoteSignResponse(w, http.StatusMethodNotAllowed, RemoteSignResponse{Error: "method not allowed"})
+			return
+		}
+
+		var request RemoteSignRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRemoteSignSize)).Decode(&request); err != nil {
+			writeRemoteSignResponse(w, http.StatusBadRequest, RemoteSignResponse{Error: err.Error()})
+			return
+		}
+		unsignedTx, boxesToSpend, dataBoxes, err := request.Decode()
+		if err != nil {
+			writeRemoteSignResponse(w, http.StatusBadRequest, RemoteSignResponse{Error: err.Error()})
+			return
+		}
+		defer unsignedTx.Close()
+		defer boxesToSpend.Close()
+		defer dataBoxes.Close()
+
+		stateContext, err := newStateContext()
+		if err != nil {
+			writeRemoteSignResponse(w, http.StatusInternalServerError, RemoteSignResponse{Error: err.Error()})
+			return
+		}
+		tx, err := signer.SignTransaction(stateContext, unsignedTx, boxesToSpend, dataBoxes)
+		if err != nil {
+			writeRemoteSignResponse(w, http.StatusUnprocessableEntity, RemoteSignResponse{Error: err.Error()})
+			return
+		}
+		defer tx.Close()
+		txJson, err := tx.Json()
+		if err != nil {
+			writeRemoteSignResponse(w, http.StatusInternalServerError, RemoteSignResponse{Error: err.Error()})
+			return
+		}
+		writeRemoteSignResponse(w, http.StatusOK, RemoteSignResponse{Tx: json.RawMessage(txJson)})
+	})
+}
+
+func writeRemoteSignResponse(w http.ResponseWriter, status int, response RemoteSignResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func boxesJson(boxes Boxes) ([]json.RawMessage, error) {
+	result := make([]json.RawMessage, 0, boxes.Len())
+	for _, box := range boxes.All() {
+		boxJson, err := box.Json()
+		box.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, json.RawMessage(boxJson))
+	}
+	return result, nil
+}
+
+func boxesFromJson(boxesJson []json.RawMessage) (Boxes, error) {
+	boxes := NewBoxes()
+	for _, boxJson := range boxesJson {
+		box, err := NewBoxFromJson(string(boxJson))
+		if err != nil {
+			boxes.Close()
+			return nil, err
+		}
+		boxes.Add(box)
+		box.Close()
+	}
+	return boxes, nil
+}
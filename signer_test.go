@@ -0,0 +1,68 @@
+package ergo
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRemoteSigner_SignTransaction(t *testing.T) {
+	sk := NewSecretKey()
+	inputContract, _ := NewContractPayToAddress(sk.Address())
+	testTxId, _ := NewTxId("93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38")
+	inputBoxVal, _ := NewBoxValue(1000000000)
+	inputBox, _ := NewBox(inputBoxVal, 0, inputContract, testTxId, 0, NewTokens())
+	unspentBoxes := NewBoxes()
+	unspentBoxes.Add(inputBox)
+
+	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
+	testContract, _ := NewContractPayToAddress(recipient)
+	outBoxValue := SafeUserMinBoxValue()
+	outbox, _ := NewBoxCandidateBuilder(outBoxValue, testContract, 0).Build()
+	txOutputs := NewBoxCandidates()
+	txOutputs.Add(outbox)
+	fee := SuggestedTxFee()
+	targetBalance, _ := SumOfBoxValues(outBoxValue, fee)
+	testBoxSelection, _ := NewSimpleBoxSelector().Select(unspentBoxes, targetBalance, NewTokens())
+	tx, _ := NewTxBuilder(testBoxSelection, txOutputs, 0, fee, recipient).Build()
+
+	testBlockHeaders := testBlockHeadersFromJson()
+	testBlockHeader, _ := testBlockHeaders.Get(0)
+	ctx, _ := NewStateContext(NewPreHeader(testBlockHeader), testBlockHeaders, DefaultParameters())
+	stateContext := func() (StateContext, error) { return ctx, nil }
+	txDataInputs := NewBoxes()
+
+	testSecretKeys := NewSecretKeys()
+	testSecretKeys.Add(sk)
+	server := httptest.NewServer(NewRemoteSignerHandler(NewWalletFromSecretKeys(testSecretKeys), stateContext))
+	defer server.Close()
+
+	var signer Signer = NewRemoteSigner(server.URL)
+	signedTx, err := signer.SignTransaction(ctx, tx, unspentBoxes, txDataInputs)
+	assert.NoError(t, err)
+	assert.NoError(t, signedTx.Validate(ctx, unspentBoxes, txDataInputs))
+
+	_, err = signer.SignMessageUsingP2PK(sk.Address(), []byte("message"))
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	// a signer without the secret key refuses
+	otherServer := httptest.NewServer(NewRemoteSignerHandler(NewWalletFromSecretKeys(NewSecretKeys()), stateContext))
+	defer otherServer.Close()
+	_, err = NewRemoteSigner(otherServer.URL).SignTransaction(ctx, tx, unspentBoxes, txDataInputs)
+	assert.ErrorContains(t, err, "remote signer")
+
+	// a signer returning another transaction than the one asked for is refused
+	otherFee, _ := NewBoxValue(2000000)
+	otherTx, _ := NewTxBuilder(testBoxSelection, txOutputs, 0, otherFee, recipient).Build()
+	otherSignedTx, err := NewWalletFromSecretKeys(testSecretKeys).SignTransaction(ctx, otherTx, unspentBoxes, txDataInputs)
+	assert.NoError(t, err)
+	otherTxJson, _ := otherSignedTx.Json()
+	swappingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(RemoteSignResponse{Tx: json.RawMessage(otherTxJson)})
+	}))
+	defer swappingServer.Close()
+	_, err = NewRemoteSigner(swappingServer.URL).SignTransaction(ctx, tx, unspentBoxes, txDataInputs)
+	assert.ErrorContains(t, err, "signed transaction differs from the unsigned transaction")
+}
@@ -4,17 +4,24 @@ package ergo
    #include "ergo.h"
 */
 import "C"
-import "runtime"
+import (
+	"runtime"
+	"sync"
+)
 
 // StateContext represents blockchain state (last headers, etc.)
 type StateContext interface {
 	// Equals checks if provided StateContext is same
 	Equals(stateContext StateContext) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.ErgoStateContextPtr
 }
 
 type stateContext struct {
-	p C.ErgoStateContextPtr
+	p  C.ErgoStateContextPtr
+	mu sync.RWMutex
 }
 
 func newStateContext(s *stateContext) StateContext {
@@ -24,6 +31,8 @@ func newStateContext(s *stateContext) StateContext {
 
 // NewStateContext creates StateContext from PreHeader and BlockHeaders
 func NewStateContext(preHeader PreHeader, headers BlockHeaders, parameters Parameters) (StateContext, error) {
+	defer readLock(headers)()
+
 	var p C.ErgoStateContextPtr
 
 	errPtr := C.ergo_lib_ergo_state_context_new(preHeader.pointer(), headers.pointer(), parameters.pointer(), &p)
@@ -39,14 +48,41 @@ func NewStateContext(preHeader PreHeader, headers BlockHeaders, parameters Param
 }
 
 func (s *stateContext) Equals(stateContext StateContext) bool {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_state_context_eq(s.p, stateContext.pointer())
 	return bool(res)
 }
 
 func (s *stateContext) pointer() C.ErgoStateContextPtr {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	return s.p
 }
 
+func (s *stateContext) rlock() {
+	s.mu.RLock()
+}
+
+func (s *stateContext) runlock() {
+	s.mu.RUnlock()
+}
+
+func (s *stateContext) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.p != nil {
+		runtime.SetFinalizer(s, nil)
+		finalizeStateContext(s)
+		s.p = nil
+	}
+}
+
 func finalizeStateContext(s *stateContext) {
 	C.ergo_lib_ergo_state_context_delete(s.p)
 }
@@ -5,8 +5,11 @@ package ergo
 */
 import "C"
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -16,6 +19,14 @@ type TokenId interface {
 	Base16() string
 	// Equals checks if provided TokenId is same
 	Equals(tokenId TokenId) bool
+	// MarshalJSON encodes the TokenId as JSON string in base16
+	MarshalJSON() ([]byte, error)
+	// MarshalText encodes the TokenId as base16
+	MarshalText() ([]byte, error)
+	// Value returns the TokenId as base16 string for storing it in a database
+	Value() (driver.Value, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.TokenIdPtr
 }
 
@@ -56,15 +67,31 @@ func NewTokenIdFromBoxId(boxId BoxId) TokenId {
 }
 
 func (t *tokenId) Equals(tokenId TokenId) bool {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_token_id_eq(t.p, tokenId.pointer())
 	return bool(res)
 }
 
+func (t *tokenId) Close() {
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTokenId(t)
+		t.p = nil
+	}
+}
+
 func finalizeTokenId(t *tokenId) {
 	C.ergo_lib_token_id_delete(t.p)
 }
 
 func (t *tokenId) Base16() string {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var outStr *C.char
 
 	C.ergo_lib_token_id_to_str(t.p, &outStr)
@@ -75,7 +102,35 @@ func (t *tokenId) Base16() string {
 	return result
 }
 
+func (t *tokenId) MarshalJSON() ([]byte, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
+	return json.Marshal(t.Base16())
+}
+
+func (t *tokenId) MarshalText() ([]byte, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
+	return []byte(t.Base16()), nil
+}
+
+func (t *tokenId) Value() (driver.Value, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
+	return t.Base16(), nil
+}
+
 func (t *tokenId) pointer() C.TokenIdPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
@@ -85,6 +140,8 @@ type TokenAmount interface {
 	Int64() int64
 	// Equals checks if provided TokenAmount is same
 	Equals(tokenAmount TokenAmount) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.TokenAmountPtr
 }
 
@@ -114,19 +171,39 @@ func NewTokenAmount(amount int64) (TokenAmount, error) {
 }
 
 func (t *tokenAmount) Int64() int64 {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	amount := C.ergo_lib_token_amount_as_i64(t.p)
 	return int64(amount)
 }
 
 func (t *tokenAmount) Equals(tokenAmount TokenAmount) bool {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_token_amount_eq(t.p, tokenAmount.pointer())
 	return bool(res)
 }
 
 func (t *tokenAmount) pointer() C.TokenAmountPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+func (t *tokenAmount) Close() {
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTokenAmount(t)
+		t.p = nil
+	}
+}
+
 func finalizeTokenAmount(t *tokenAmount) {
 	C.ergo_lib_token_amount_delete(t.p)
 }
@@ -141,6 +218,8 @@ type Token interface {
 	JsonEIP12() (string, error)
 	// Equals checks if provided Token is same
 	Equals(token Token) bool
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.TokenPtr
 }
 
@@ -165,6 +244,10 @@ func NewToken(tokenId TokenId, tokenAmount TokenAmount) Token {
 }
 
 func (t *token) Id() TokenId {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var tokenIdPtr C.TokenIdPtr
 	C.ergo_lib_token_get_id(t.p, &tokenIdPtr)
 
@@ -174,6 +257,10 @@ func (t *token) Id() TokenId {
 }
 
 func (t *token) Amount() TokenAmount {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var tokenAmountPtr C.TokenAmountPtr
 	C.ergo_lib_token_get_amount(t.p, &tokenAmountPtr)
 
@@ -183,6 +270,10 @@ func (t *token) Amount() TokenAmount {
 }
 
 func (t *token) JsonEIP12() (string, error) {
+	if t.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_token_to_json_eip12(t.p, &outStr)
@@ -199,14 +290,30 @@ func (t *token) JsonEIP12() (string, error) {
 }
 
 func (t *token) Equals(token Token) bool {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_token_eq(t.p, token.pointer())
 	return bool(res)
 }
 
 func (t *token) pointer() C.TokenPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+func (t *token) Close() {
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeToken(t)
+		t.p = nil
+	}
+}
+
 func finalizeToken(t *token) {
 	C.ergo_lib_token_delete(t.p)
 }
@@ -221,11 +328,15 @@ type Tokens interface {
 	Add(token Token)
 	// All returns an iterator over all Token inside the collection
 	All() iter.Seq2[int, Token]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.TokensPtr
 }
 
 type tokens struct {
-	p C.TokensPtr
+	p  C.TokensPtr
+	mu sync.RWMutex
 }
 
 func newTokens(t *tokens) Tokens {
@@ -244,11 +355,37 @@ func NewTokens() Tokens {
 }
 
 func (t *tokens) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
+	return t.len()
+}
+
+func (t *tokens) len() int {
+	if t.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_tokens_len(t.p)
 	return int(res)
 }
 
 func (t *tokens) Get(index int) (Token, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.get(index)
+}
+
+func (t *tokens) get(index int) (Token, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.TokenPtr
 
 	res := C.ergo_lib_tokens_get(t.p, C.uintptr_t(index), &p)
@@ -263,27 +400,54 @@ func (t *tokens) Get(index int) (Token, error) {
 }
 
 func (t *tokens) Add(token Token) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_tokens_add(token.pointer(), t.p)
 }
 
 func (t *tokens) All() iter.Seq2[int, Token] {
-	return func(yield func(int, Token) bool) {
-		for i := 0; i < t.Len(); i++ {
-			tk, err := t.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(t, t.len, t.get)
 }
 
 func (t *tokens) pointer() C.TokensPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+func (t *tokens) rlock() {
+	t.mu.RLock()
+}
+
+func (t *tokens) runlock() {
+	t.mu.RUnlock()
+}
+
+func (t *tokens) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTokens(t)
+		t.p = nil
+	}
+}
+
 func finalizeTokens(t *tokens) {
 	C.ergo_lib_tokens_delete(t.p)
 }
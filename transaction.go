@@ -5,8 +5,11 @@ package ergo
 */
 import "C"
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"iter"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -16,6 +19,14 @@ type TxId interface {
 	String() (string, error)
 	// Equals checks if provided TxId is same
 	Equals(txId TxId) bool
+	// MarshalJSON encodes the TxId as JSON string in base16
+	MarshalJSON() ([]byte, error)
+	// MarshalText encodes the TxId as base16
+	MarshalText() ([]byte, error)
+	// Value returns the TxId as base16 string for storing it in a database
+	Value() (driver.Value, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.TxIdPtr
 }
 
@@ -48,6 +59,10 @@ func NewTxId(s string) (TxId, error) {
 }
 
 func (t *txId) String() (string, error) {
+	if t.p == nil {
+		return "", ErrClosed
+	}
+
 	var outTxIdStr *C.char
 
 	errPtr := C.ergo_lib_tx_id_to_str(t.p, &outTxIdStr)
@@ -61,14 +76,52 @@ func (t *txId) String() (string, error) {
 }
 
 func (t *txId) Equals(txId TxId) bool {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_tx_id_eq(t.p, txId.pointer())
 	return bool(res)
 }
 
+func (t *txId) MarshalJSON() ([]byte, error) {
+	s, err := t.String()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(s)
+}
+
+func (t *txId) MarshalText() ([]byte, error) {
+	s, err := t.String()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+func (t *txId) Value() (driver.Value, error) {
+	return t.String()
+}
+
 func (t *txId) pointer() C.TxIdPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+func (t *txId) Close() {
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTxId(t)
+		t.p = nil
+	}
+}
+
 func finalizeTxId(t *txId) {
 	C.ergo_lib_tx_id_delete(t.p)
 }
@@ -76,11 +129,16 @@ func finalizeTxId(t *txId) {
 // CommitmentHint is a family of hints which are about a correspondence between a public image of a secret image and prover's commitment
 // to randomness ("a" in a sigma protocol).
 type CommitmentHint interface {
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.CommitmentHintPtr
+	commitmentIds() []string
 }
 
 type commitmentHint struct {
 	p C.CommitmentHintPtr
+	// commitments identifies the generated commitments the hint was taken from, see CommitmentStore
+	commitments []string
 }
 
 func newCommitmentHint(c *commitmentHint) CommitmentHint {
@@ -89,9 +147,25 @@ func newCommitmentHint(c *commitmentHint) CommitmentHint {
 }
 
 func (c *commitmentHint) pointer() C.CommitmentHintPtr {
+	if c.p == nil {
+		panic(ErrClosed)
+	}
+
 	return c.p
 }
 
+func (c *commitmentHint) commitmentIds() []string {
+	return c.commitments
+}
+
+func (c *commitmentHint) Close() {
+	if c.p != nil {
+		runtime.SetFinalizer(c, nil)
+		finalizeCommitmentHint(c)
+		c.p = nil
+	}
+}
+
 func finalizeCommitmentHint(c *commitmentHint) {
 	C.ergo_lib_commitment_hint_delete(c.p)
 }
@@ -106,11 +180,19 @@ type HintsBag interface {
 	Get(index int) (CommitmentHint, error)
 	// All returns an iterator over all CommitmentHint inside the collection
 	All() iter.Seq2[int, CommitmentHint]
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.HintsBagPtr
+	commitmentIds() []string
 }
 
 type hintsBag struct {
-	p C.HintsBagPtr
+	p  C.HintsBagPtr
+	mu sync.RWMutex
+	// hintCommitments are the commitment ids of the hints, indexed like Get. It may be shorter than the bag,
+	// the hints beyond it have no ids
+	hintCommitments [][]string
 }
 
 func newHintsBag(h *hintsBag) HintsBag {
@@ -128,15 +210,53 @@ func NewHintsBag() HintsBag {
 }
 
 func (h *hintsBag) Add(hint CommitmentHint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.p == nil {
+		panic(ErrClosed)
+	}
+
+	index := int(C.ergo_lib_hints_bag_len(h.p))
 	C.ergo_lib_hints_bag_add_commitment(h.p, hint.pointer())
+	if ids := hint.commitmentIds(); len(ids) > 0 {
+		h.hintCommitments = append(h.hintCommitments, make([][]string, index-len(h.hintCommitments))...)
+		h.hintCommitments = append(h.hintCommitments, ids)
+	}
 }
 
 func (h *hintsBag) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.p == nil {
+		panic(ErrClosed)
+	}
+
+	return h.len()
+}
+
+func (h *hintsBag) len() int {
+	if h.p == nil {
+		return 0
+	}
+
 	res := C.ergo_lib_hints_bag_len(h.p)
 	return int(res)
 }
 
 func (h *hintsBag) Get(index int) (CommitmentHint, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.get(index)
+}
+
+func (h *hintsBag) get(index int) (CommitmentHint, error) {
+	if h.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.CommitmentHintPtr
 
 	res := C.ergo_lib_hints_bag_get(h.p, C.uintptr_t(index), &p)
@@ -147,6 +267,9 @@ func (h *hintsBag) Get(index int) (CommitmentHint, error) {
 
 	if res.is_some {
 		c := &commitmentHint{p: p}
+		if index >= 0 && index < len(h.hintCommitments) {
+			c.commitments = h.hintCommitments[index]
+		}
 		return newCommitmentHint(c), nil
 	}
 
@@ -154,37 +277,81 @@ func (h *hintsBag) Get(index int) (CommitmentHint, error) {
 }
 
 func (h *hintsBag) All() iter.Seq2[int, CommitmentHint] {
-	return func(yield func(int, CommitmentHint) bool) {
-		for i := 0; i < h.Len(); i++ {
-			tk, err := h.Get(i)
-			if err != nil {
-				return
-			}
-			if !yield(i, tk) {
-				return
-			}
-		}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.p == nil {
+		panic(ErrClosed)
 	}
+
+	return readLockedAll(h, h.len, h.get)
 }
 
 func (h *hintsBag) pointer() C.HintsBagPtr {
+	if h.p == nil {
+		panic(ErrClosed)
+	}
+
 	return h.p
 }
 
+// commitmentIds must be called with the read lock held
+func (h *hintsBag) commitmentIds() []string {
+	var ids []string
+	for _, hintIds := range h.hintCommitments {
+		ids = mergeCommitmentIds(ids, hintIds)
+	}
+	return ids
+}
+
+func (h *hintsBag) rlock() {
+	h.mu.RLock()
+}
+
+func (h *hintsBag) runlock() {
+	h.mu.RUnlock()
+}
+
+func (h *hintsBag) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.p != nil {
+		runtime.SetFinalizer(h, nil)
+		finalizeHintsBag(h)
+		h.p = nil
+	}
+}
+
 func finalizeHintsBag(h *hintsBag) {
 	C.ergo_lib_hints_bag_delete(h.p)
 }
 
+// TransactionHintsBag holds the HintsBag of every input of a transaction.
+//
+// ergo-lib-c neither serializes hints nor tells public from secret commitments, so a TransactionHintsBag
+// can only be passed between cosigners within the same process. Cosigners on other machines have to exchange
+// hints through the Ergo node, whose /wallet/generateCommitments and /wallet/transaction/sign endpoints accept
+// and return them as JSON.
 type TransactionHintsBag interface {
 	// AddHintsForInput adds hints for input
 	AddHintsForInput(index uint32, hintsBag HintsBag)
 	// AllHintsForInput gets HintsBag corresponding to input index
 	AllHintsForInput(index uint32) HintsBag
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.TransactionHintsBagPtr
+	commitmentIds() []string
 }
 
 type transactionHintsBag struct {
-	p C.TransactionHintsBagPtr
+	p  C.TransactionHintsBagPtr
+	mu sync.RWMutex
+	// commitments are the commitment ids of all inputs, set for the bag returned by GenerateCommitments
+	commitments []string
+	// inputCommitments are the commitment ids of the hints added by AddHintsForInput
+	inputCommitments map[uint32][]string
 }
 
 func newTransactionHintsBag(t *transactionHintsBag) TransactionHintsBag {
@@ -203,20 +370,80 @@ func NewTransactionHintsBag() TransactionHintsBag {
 }
 
 func (t *transactionHintsBag) AddHintsForInput(index uint32, hintsBag HintsBag) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer readLock(hintsBag)()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_transaction_hints_bag_add_hints_for_input(t.p, C.uintptr_t(index), hintsBag.pointer())
+	// ergo-lib-c replaces the hints of the input
+	if t.inputCommitments == nil {
+		t.inputCommitments = make(map[uint32][]string)
+	}
+	t.inputCommitments[index] = hintsBag.commitmentIds()
 }
 
 func (t *transactionHintsBag) AllHintsForInput(index uint32) HintsBag {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.HintsBagPtr
 	C.ergo_lib_transaction_hints_bag_all_hints_for_input(t.p, C.uintptr_t(index), &p)
 	h := &hintsBag{p: p}
+	// ergo-lib-c orders the hints of an input by their kind, so which hint belongs to which commitment id
+	// is unknown and every hint carries all ids of the input
+	if ids := mergeCommitmentIds(t.commitments, t.inputCommitments[index]); len(ids) > 0 {
+		h.hintCommitments = make([][]string, int(C.ergo_lib_hints_bag_len(p)))
+		for i := range h.hintCommitments {
+			h.hintCommitments[i] = ids
+		}
+	}
 	return newHintsBag(h)
 }
 
 func (t *transactionHintsBag) pointer() C.TransactionHintsBagPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+// commitmentIds must be called with the read lock held
+func (t *transactionHintsBag) commitmentIds() []string {
+	ids := t.commitments
+	for _, inputIds := range t.inputCommitments {
+		ids = mergeCommitmentIds(ids, inputIds)
+	}
+	return ids
+}
+
+func (t *transactionHintsBag) rlock() {
+	t.mu.RLock()
+}
+
+func (t *transactionHintsBag) runlock() {
+	t.mu.RUnlock()
+}
+
+func (t *transactionHintsBag) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTransactionHintsBag(t)
+		t.p = nil
+	}
+}
+
 func finalizeTransactionHintsBag(t *transactionHintsBag) {
 	C.ergo_lib_transaction_hints_bag_delete(t.p)
 }
@@ -229,6 +456,8 @@ func ExtractHintsFromSignedTransaction(
 	dataBoxes Boxes,
 	realPropositions Propositions,
 	simulatedPropositions Propositions) (TransactionHintsBag, error) {
+	defer readLock(boxesToSpend, dataBoxes, realPropositions, simulatedPropositions)()
+
 	var p C.TransactionHintsBagPtr
 
 	errPtr := C.ergo_lib_transaction_extract_hints(
@@ -264,11 +493,18 @@ type UnsignedTransaction interface {
 	Json() (string, error)
 	// JsonEIP12 returns json representation of UnsignedTransaction as string according to EIP-12 https://github.com/ergoplatform/eips/pull/23
 	JsonEIP12() (string, error)
+	// Bytes returns the UnsignedTransaction in the binary format used by the node with empty proofs.
+	// These are the bytes to sign, their blake2b256 hash is the TxId
+	Bytes() ([]byte, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
+	locker
 	pointer() C.UnsignedTransactionPtr
 }
 
 type unsignedTransaction struct {
-	p C.UnsignedTransactionPtr
+	p  C.UnsignedTransactionPtr
+	mu sync.RWMutex
 }
 
 func newUnsignedTransaction(u *unsignedTransaction) UnsignedTransaction {
@@ -276,6 +512,21 @@ func newUnsignedTransaction(u *unsignedTransaction) UnsignedTransaction {
 	return u
 }
 
+// NewUnsignedTransactionFromBytes parses UnsignedTransaction from the binary format returned by
+// UnsignedTransaction.Bytes. Proofs contained in the bytes are ignored
+func NewUnsignedTransactionFromBytes(bytes []byte) (UnsignedTransaction, error) {
+	tx, err := parseTransaction(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	txJson, err := tx.unsignedJson()
+	if err != nil {
+		return nil, err
+	}
+	return NewUnsignedTransactionFromJson(txJson)
+}
+
 // NewUnsignedTransactionFromJson parse UnsignedTransaction from JSON. Supports Ergo Node/Explorer API and box values and token amount encoded as strings.
 func NewUnsignedTransactionFromJson(json string) (UnsignedTransaction, error) {
 	unsTxJsonStr := C.CString(json)
@@ -295,6 +546,10 @@ func NewUnsignedTransactionFromJson(json string) (UnsignedTransaction, error) {
 }
 
 func (u *unsignedTransaction) TxId() TxId {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.TxIdPtr
 	C.ergo_lib_unsigned_tx_id(u.p, &p)
 	ti := &txId{p: p}
@@ -302,6 +557,10 @@ func (u *unsignedTransaction) TxId() TxId {
 }
 
 func (u *unsignedTransaction) UnsignedInputs() UnsignedInputs {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.UnsignedInputsPtr
 	C.ergo_lib_unsigned_tx_inputs(u.p, &p)
 	ui := &unsignedInputs{p: p}
@@ -309,6 +568,10 @@ func (u *unsignedTransaction) UnsignedInputs() UnsignedInputs {
 }
 
 func (u *unsignedTransaction) DataInputs() DataInputs {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.DataInputsPtr
 	C.ergo_lib_unsigned_tx_data_inputs(u.p, &p)
 	di := &dataInputs{p: p}
@@ -316,6 +579,10 @@ func (u *unsignedTransaction) DataInputs() DataInputs {
 }
 
 func (u *unsignedTransaction) OutputCandidates() BoxCandidates {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoBoxCandidatesPtr
 	C.ergo_lib_unsigned_tx_output_candidates(u.p, &p)
 	bc := &boxCandidates{p: p}
@@ -323,6 +590,10 @@ func (u *unsignedTransaction) OutputCandidates() BoxCandidates {
 }
 
 func (u *unsignedTransaction) Json() (string, error) {
+	if u.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_unsigned_tx_to_json(u.p, &outStr)
@@ -339,6 +610,10 @@ func (u *unsignedTransaction) Json() (string, error) {
 }
 
 func (u *unsignedTransaction) JsonEIP12() (string, error) {
+	if u.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_unsigned_tx_to_json_eip12(u.p, &outStr)
@@ -355,9 +630,65 @@ func (u *unsignedTransaction) JsonEIP12() (string, error) {
 }
 
 func (u *unsignedTransaction) pointer() C.UnsignedTransactionPtr {
+	if u.p == nil {
+		panic(ErrClosed)
+	}
+
 	return u.p
 }
 
+func (u *unsignedTransaction) rlock() {
+	u.mu.RLock()
+}
+
+func (u *unsignedTransaction) runlock() {
+	u.mu.RUnlock()
+}
+
+func (u *unsignedTransaction) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.p != nil {
+		runtime.SetFinalizer(u, nil)
+		finalizeUnsignedTransaction(u)
+		u.p = nil
+	}
+}
+
+func (u *unsignedTransaction) Bytes() ([]byte, error) {
+	if u.p == nil {
+		return nil, ErrClosed
+	}
+
+	var tx serializedTransaction
+
+	unsignedInputs := u.UnsignedInputs()
+	defer unsignedInputs.Close()
+	for _, input := range unsignedInputs.All() {
+		boxId, err := boxIdBytes(input.BoxId())
+		if err != nil {
+			return nil, err
+		}
+		extension, err := extensionFields(input.ContextExtension())
+		input.Close()
+		if err != nil {
+			return nil, err
+		}
+		tx.inputs = append(tx.inputs, serializedInput{boxId: boxId, extension: extension})
+	}
+
+	var err error
+	if tx.dataInputs, err = dataInputsFields(u.DataInputs()); err != nil {
+		return nil, err
+	}
+	if tx.outputs, err = candidatesFields(u.OutputCandidates()); err != nil {
+		return nil, err
+	}
+
+	return tx.bytes(), nil
+}
+
 func finalizeUnsignedTransaction(u *unsignedTransaction) {
 	C.ergo_lib_unsigned_tx_delete(u.p)
 }
@@ -387,6 +718,12 @@ type Transaction interface {
 	JsonEIP12() (string, error)
 	// Validate validates the current Transaction
 	Validate(stateContext StateContext, boxesToSpent Boxes, dataBoxes Boxes) error
+	// MarshalJSON encodes the Transaction as JSON in the format returned by Json
+	MarshalJSON() ([]byte, error)
+	// Bytes returns the Transaction in the binary format used by the node
+	Bytes() ([]byte, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.TransactionPtr
 }
 
@@ -402,6 +739,8 @@ func newTransaction(t *transaction) Transaction {
 // NewTransaction creates Transaction from UnsignedTransaction and an array of proofs in the same order as
 // UnsignedTransaction inputs with empty proof indicated with empty ByteArray
 func NewTransaction(unsignedTx UnsignedTransaction, proofs ByteArrays) (Transaction, error) {
+	defer readLock(proofs)()
+
 	var p C.TransactionPtr
 
 	errPtr := C.ergo_lib_tx_from_unsigned_tx(unsignedTx.pointer(), proofs.pointer(), &p)
@@ -415,6 +754,37 @@ func NewTransaction(unsignedTx UnsignedTransaction, proofs ByteArrays) (Transact
 	return newTransaction(t), nil
 }
 
+// NewTransactionFromBytes parses Transaction from the binary format returned by Transaction.Bytes
+func NewTransactionFromBytes(bytes []byte) (Transaction, error) {
+	tx, err := parseTransaction(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	txJson, err := tx.unsignedJson()
+	if err != nil {
+		return nil, err
+	}
+	unsignedTx, err := NewUnsignedTransactionFromJson(txJson)
+	if err != nil {
+		return nil, err
+	}
+	defer unsignedTx.Close()
+
+	proofs := NewByteArrays()
+	defer proofs.Close()
+	for _, input := range tx.inputs {
+		proof, err := NewByteArray(input.proof)
+		if err != nil {
+			return nil, err
+		}
+		proofs.Add(proof)
+		proof.Close()
+	}
+
+	return NewTransaction(unsignedTx, proofs)
+}
+
 // NewTransactionFromJson parse Transaction from JSON. Supports Ergo Node/Explorer API and box values and token amount encoded as strings.
 func NewTransactionFromJson(json string) (Transaction, error) {
 	txJsonStr := C.CString(json)
@@ -434,6 +804,10 @@ func NewTransactionFromJson(json string) (Transaction, error) {
 }
 
 func (t *transaction) TxId() TxId {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.TxIdPtr
 	C.ergo_lib_tx_id(t.p, &p)
 	ti := &txId{p: p}
@@ -441,6 +815,10 @@ func (t *transaction) TxId() TxId {
 }
 
 func (t *transaction) Inputs() Inputs {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.InputsPtr
 	C.ergo_lib_tx_inputs(t.p, &p)
 	i := &inputs{p: p}
@@ -448,6 +826,10 @@ func (t *transaction) Inputs() Inputs {
 }
 
 func (t *transaction) DataInputs() DataInputs {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.DataInputsPtr
 	C.ergo_lib_tx_data_inputs(t.p, &p)
 	di := &dataInputs{p: p}
@@ -455,6 +837,10 @@ func (t *transaction) DataInputs() DataInputs {
 }
 
 func (t *transaction) OutputCandidates() BoxCandidates {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoBoxCandidatesPtr
 	C.ergo_lib_tx_output_candidates(t.p, &p)
 	bc := &boxCandidates{p: p}
@@ -462,6 +848,10 @@ func (t *transaction) OutputCandidates() BoxCandidates {
 }
 
 func (t *transaction) Outputs() Boxes {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoBoxesPtr
 	C.ergo_lib_tx_outputs(t.p, &p)
 	b := &boxes{p: p}
@@ -469,6 +859,10 @@ func (t *transaction) Outputs() Boxes {
 }
 
 func (t *transaction) Json() (string, error) {
+	if t.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_tx_to_json(t.p, &outStr)
@@ -485,6 +879,10 @@ func (t *transaction) Json() (string, error) {
 }
 
 func (t *transaction) JsonEIP12() (string, error) {
+	if t.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_tx_to_json_eip12(t.p, &outStr)
@@ -501,6 +899,12 @@ func (t *transaction) JsonEIP12() (string, error) {
 }
 
 func (t *transaction) Validate(stateContext StateContext, boxesToSpent Boxes, dataBoxes Boxes) error {
+	defer readLock(boxesToSpent, dataBoxes)()
+
+	if t.p == nil {
+		return ErrClosed
+	}
+
 	errPtr := C.ergo_lib_tx_validate(t.p, stateContext.pointer(), boxesToSpent.pointer(), dataBoxes.pointer())
 	err := newError(errPtr)
 	if err.isError() {
@@ -509,10 +913,67 @@ func (t *transaction) Validate(stateContext StateContext, boxesToSpent Boxes, da
 	return nil
 }
 
+func (t *transaction) MarshalJSON() ([]byte, error) {
+	s, err := t.Json()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+func (t *transaction) Bytes() ([]byte, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
+	var tx serializedTransaction
+
+	txInputs := t.Inputs()
+	defer txInputs.Close()
+	for _, input := range txInputs.All() {
+		boxId, err := boxIdBytes(input.BoxId())
+		if err != nil {
+			return nil, err
+		}
+		spendingProof := input.SpendingProof()
+		proof := spendingProof.Bytes()
+		extension, err := extensionFields(spendingProof.ContextExtension())
+		spendingProof.Close()
+		input.Close()
+		if err != nil {
+			return nil, err
+		}
+		tx.inputs = append(tx.inputs, serializedInput{boxId: boxId, proof: proof, extension: extension})
+	}
+
+	var err error
+	if tx.dataInputs, err = dataInputsFields(t.DataInputs()); err != nil {
+		return nil, err
+	}
+	if tx.outputs, err = candidatesFields(t.OutputCandidates()); err != nil {
+		return nil, err
+	}
+
+	return tx.bytes(), nil
+}
+
 func (t *transaction) pointer() C.TransactionPtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+func (t *transaction) Close() {
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTransaction(t)
+		t.p = nil
+	}
+}
+
 func finalizeTransaction(t *transaction) {
 	C.ergo_lib_tx_delete(t.p)
 }
@@ -1,8 +1,11 @@
 package ergo
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
+	"math"
+	"sync"
 	"testing"
 )
 
@@ -142,6 +145,104 @@ func TestWallet_SignTransaction(t *testing.T) {
 	assert.NoError(t, validationErr)
 }
 
+func TestWallet_SignTransaction_Concurrent(t *testing.T) {
+	sk := NewSecretKey()
+	inputContract, _ := NewContractPayToAddress(sk.Address())
+	testTxId, _ := NewTxId("93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38")
+	inputBoxVal, _ := NewBoxValue(1000000000)
+	inputBox, _ := NewBox(inputBoxVal, 0, inputContract, testTxId, 0, NewTokens())
+
+	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
+	unspentBoxes := NewBoxes()
+	unspentBoxes.Add(inputBox)
+	testContract, _ := NewContractPayToAddress(recipient)
+	outBoxValue := SafeUserMinBoxValue()
+	outbox, _ := NewBoxCandidateBuilder(outBoxValue, testContract, 0).Build()
+	txOutputs := NewBoxCandidates()
+	txOutputs.Add(outbox)
+	fee := SuggestedTxFee()
+	targetBalance, _ := SumOfBoxValues(outBoxValue, fee)
+	testBoxSelection, _ := NewSimpleBoxSelector().Select(unspentBoxes, targetBalance, NewTokens())
+	tx, _ := NewTxBuilder(testBoxSelection, txOutputs, 0, fee, recipient).Build()
+
+	testBlockHeaders := testBlockHeadersFromJson()
+	testBlockHeader, _ := testBlockHeaders.Get(0)
+	ctx, _ := NewStateContext(NewPreHeader(testBlockHeader), testBlockHeaders, DefaultParameters())
+	testWallet := NewWalletFromSecretKeys(NewSecretKeys())
+	assert.NoError(t, testWallet.AddSecret(sk))
+	txDataInputs := NewBoxes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			signedTx, signingErr := testWallet.SignTransaction(ctx, tx, unspentBoxes, txDataInputs)
+			assert.NoError(t, signingErr)
+			assert.NoError(t, signedTx.Validate(ctx, unspentBoxes, txDataInputs))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, testWallet.AddSecret(NewSecretKey()))
+			txDataInputs.Len()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTransaction_Bytes(t *testing.T) {
+	sk := NewSecretKey()
+	inputContract, _ := NewContractPayToAddress(sk.Address())
+	testTxId, _ := NewTxId("93d344aa527e18e5a221db060ea1a868f46b61e4537e6e5f69ecc40334c15e38")
+	inputBoxVal, _ := NewBoxValue(1000000000)
+	inputBox, _ := NewBox(inputBoxVal, 0, inputContract, testTxId, 0, NewTokens())
+
+	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
+	unspentBoxes := NewBoxes()
+	unspentBoxes.Add(inputBox)
+	testContract, _ := NewContractPayToAddress(recipient)
+	outBoxValue := SafeUserMinBoxValue()
+	outboxBuilder := NewBoxCandidateBuilder(outBoxValue, testContract, 0)
+	outboxBuilder.SetRegisterValue(R4, NewConstantFromInt64(42))
+	outbox, _ := outboxBuilder.Build()
+	txOutputs := NewBoxCandidates()
+	txOutputs.Add(outbox)
+	fee := SuggestedTxFee()
+	targetBalance, _ := SumOfBoxValues(outBoxValue, fee)
+	testBoxSelection, _ := NewSimpleBoxSelector().Select(unspentBoxes, targetBalance, NewTokens())
+	tx, _ := NewTxBuilder(testBoxSelection, txOutputs, 0, fee, recipient).Build()
+
+	unsignedBytes, unsignedErr := tx.Bytes()
+	assert.NoError(t, unsignedErr)
+	parsedUnsignedTx, parseUnsignedErr := NewUnsignedTransactionFromBytes(unsignedBytes)
+	assert.NoError(t, parseUnsignedErr)
+	assert.Equal(t, txIdString(t, tx.TxId()), txIdString(t, parsedUnsignedTx.TxId()))
+	reencodedUnsignedBytes, _ := parsedUnsignedTx.Bytes()
+	assert.Equal(t, unsignedBytes, reencodedUnsignedBytes)
+
+	testBlockHeaders := testBlockHeadersFromJson()
+	testBlockHeader, _ := testBlockHeaders.Get(0)
+	ctx, _ := NewStateContext(NewPreHeader(testBlockHeader), testBlockHeaders, DefaultParameters())
+	testSecretKeys := NewSecretKeys()
+	testSecretKeys.Add(sk)
+	signedTx, _ := NewWalletFromSecretKeys(testSecretKeys).SignTransaction(ctx, tx, unspentBoxes, NewBoxes())
+
+	signedBytes, signedErr := signedTx.Bytes()
+	assert.NoError(t, signedErr)
+	assert.Greater(t, len(signedBytes), len(unsignedBytes))
+
+	parsedTx, parseErr := NewTransactionFromBytes(signedBytes)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, txIdString(t, signedTx.TxId()), txIdString(t, parsedTx.TxId()))
+	assert.NoError(t, parsedTx.Validate(ctx, unspentBoxes, NewBoxes()))
+
+	reencodedBytes, _ := parsedTx.Bytes()
+	assert.Equal(t, signedBytes, reencodedBytes)
+
+	_, trailingErr := NewTransactionFromBytes(append(signedBytes, 0))
+	assert.Error(t, trailingErr)
+}
+
 func TestMintToken(t *testing.T) {
 	recipient, _ := NewAddress("3WvsT2Gm4EpsM9Pg18PdY6XyhNNMqXDsvJTbbf6ihLvAmSb7u5RN")
 	boxJson := `{
@@ -326,3 +427,19 @@ func TestMultiSigTx(t *testing.T) {
 	validationErr := signedTx.Validate(ctx, unspentBoxes, txDataInputs)
 	assert.NoError(t, validationErr)
 }
+
+func TestParseTransaction_CreationHeightOutOfRange(t *testing.T) {
+	// no inputs, data inputs and tokens, one output with a sized tree and a creation height of 2^32
+	b := []byte{0, 0, 0, 1, 1, 0x08, 1, 0x7f}
+	b = binary.AppendUvarint(b, math.MaxUint32+1)
+	b = append(b, 0, 0)
+
+	_, err := parseTransaction(b)
+	assert.ErrorContains(t, err, "creation height 4294967296 out of range")
+}
+
+func txIdString(t *testing.T, txId TxId) string {
+	s, err := txId.String()
+	assert.NoError(t, err)
+	return s
+}
@@ -6,7 +6,9 @@ package ergo
 import "C"
 import (
 	"crypto/sha256"
+	"database/sql/driver"
 	"encoding/hex"
+	"encoding/json"
 	"runtime"
 	"unsafe"
 )
@@ -29,6 +31,14 @@ type Tree interface {
 	Constants() ([]Constant, error)
 	// Equals checks if provided Tree is same
 	Equals(tree Tree) bool
+	// MarshalJSON encodes the Tree as JSON string in base16
+	MarshalJSON() ([]byte, error)
+	// MarshalText encodes the Tree as base16
+	MarshalText() ([]byte, error)
+	// Value returns the Tree as base16 string for storing it in a database
+	Value() (driver.Value, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.ErgoTreePtr
 }
 
@@ -61,6 +71,10 @@ func NewTree(s string) (Tree, error) {
 }
 
 func (t *tree) Base16() (string, error) {
+	if t.p == nil {
+		return "", ErrClosed
+	}
+
 	var outStr *C.char
 
 	errPtr := C.ergo_lib_ergo_tree_to_base16_bytes(t.p, &outStr)
@@ -77,6 +91,10 @@ func (t *tree) Base16() (string, error) {
 }
 
 func (t *tree) Address() (Address, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.AddressPtr
 
 	errPtr := C.ergo_lib_address_from_ergo_tree(t.p, &p)
@@ -92,6 +110,10 @@ func (t *tree) Address() (Address, error) {
 }
 
 func (t *tree) TemplateBytesLength() (int, error) {
+	if t.p == nil {
+		return 0, ErrClosed
+	}
+
 	var returnNum C.ReturnNum_usize
 	returnNum = C.ergo_lib_ergo_tree_template_bytes_len(t.p)
 	err := newError(returnNum.error)
@@ -105,6 +127,10 @@ func (t *tree) TemplateBytesLength() (int, error) {
 }
 
 func (t *tree) TemplateHash() (string, error) {
+	if t.p == nil {
+		return "", ErrClosed
+	}
+
 	bytesLength, byteErr := t.TemplateBytesLength()
 	if byteErr != nil {
 		return "", byteErr
@@ -127,6 +153,10 @@ func (t *tree) TemplateHash() (string, error) {
 }
 
 func (t *tree) ConstantsLength() (int, error) {
+	if t.p == nil {
+		return 0, ErrClosed
+	}
+
 	var returnNum C.ReturnNum_usize
 	returnNum = C.ergo_lib_ergo_tree_constants_len(t.p)
 	err := newError(returnNum.error)
@@ -140,6 +170,10 @@ func (t *tree) ConstantsLength() (int, error) {
 }
 
 func (t *tree) Constant(index int) (Constant, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
 	var constantOut C.ConstantPtr
 	var returnOption C.ReturnOption
 
@@ -158,6 +192,10 @@ func (t *tree) Constant(index int) (Constant, error) {
 }
 
 func (t *tree) Constants() ([]Constant, error) {
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
 	length, err := t.ConstantsLength()
 	if err != nil {
 		return nil, err
@@ -174,14 +212,52 @@ func (t *tree) Constants() ([]Constant, error) {
 }
 
 func (t *tree) Equals(tree Tree) bool {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_ergo_tree_eq(t.p, tree.pointer())
 	return bool(res)
 }
 
+func (t *tree) MarshalJSON() ([]byte, error) {
+	s, err := t.Base16()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(s)
+}
+
+func (t *tree) MarshalText() ([]byte, error) {
+	s, err := t.Base16()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+func (t *tree) Value() (driver.Value, error) {
+	return t.Base16()
+}
+
 func (t *tree) pointer() C.ErgoTreePtr {
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	return t.p
 }
 
+func (t *tree) Close() {
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTree(t)
+		t.p = nil
+	}
+}
+
 func finalizeTree(t *tree) {
 	C.ergo_lib_ergo_tree_delete(t.p)
 }
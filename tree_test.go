@@ -82,3 +82,13 @@ func TestTree_ErgoTreeGetConstants(t *testing.T) {
 	assert.Equal(t, "SColl(SInt)", consType3)
 	assert.Equal(t, "SInt", consType14)
 }
+
+func TestTree_Close(t *testing.T) {
+	tree, _ := NewTree("0008cd0336100ef59ced80ba5f89c4178ebd57b6c1dd0f3d135ee1db9f62fc634d637041")
+	tree.Close()
+	tree.Close()
+
+	_, err := tree.Base16()
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.PanicsWithValue(t, ErrClosed, func() { tree.Equals(tree) })
+}
@@ -4,7 +4,10 @@ package ergo
 #include "ergo.h"
 */
 import "C"
-import "runtime"
+import (
+	"runtime"
+	"sync"
+)
 
 // TxBuilder builds UnsignedTransaction
 type TxBuilder interface {
@@ -28,10 +31,13 @@ type TxBuilder interface {
 	ChangeAddress() Address
 	// Build builds the UnsignedTransaction
 	Build() (UnsignedTransaction, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type txBuilder struct {
-	p C.TxBuilderPtr
+	p  C.TxBuilderPtr
+	mu sync.RWMutex
 }
 
 func newTxBuilder(t *txBuilder) TxBuilder {
@@ -52,6 +58,8 @@ func NewTxBuilder(
 	currentHeight uint32,
 	feeAmount BoxValue,
 	changeAddress Address) TxBuilder {
+	defer readLock(outputCandidates)()
+
 	var p C.TxBuilderPtr
 	C.ergo_lib_tx_builder_new(
 		boxSelection.pointer(),
@@ -65,18 +73,49 @@ func NewTxBuilder(
 }
 
 func (t *txBuilder) SetDataInputs(dataInputs DataInputs) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer readLock(dataInputs)()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_tx_builder_set_data_inputs(t.p, dataInputs.pointer())
 }
 
 func (t *txBuilder) SetContextExtension(boxId BoxId, contextExtension ContextExtension) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer readLock(contextExtension)()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_tx_builder_set_context_extension(t.p, boxId.pointer(), contextExtension.pointer())
 }
 
 func (t *txBuilder) SetTokenBurnPermit(tokens Tokens) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer readLock(tokens)()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	C.ergo_lib_tx_builder_set_token_burn_permit(t.p, tokens.pointer())
 }
 
 func (t *txBuilder) DataInputs() DataInputs {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.DataInputsPtr
 	C.ergo_lib_tx_builder_data_inputs(t.p, &p)
 	di := &dataInputs{p: p}
@@ -84,6 +123,13 @@ func (t *txBuilder) DataInputs() DataInputs {
 }
 
 func (t *txBuilder) BoxSelection() BoxSelection {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxSelectionPtr
 	C.ergo_lib_tx_builder_box_selection(t.p, &p)
 	bs := &boxSelection{p: p}
@@ -91,6 +137,13 @@ func (t *txBuilder) BoxSelection() BoxSelection {
 }
 
 func (t *txBuilder) OutputCandidates() BoxCandidates {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.ErgoBoxCandidatesPtr
 	C.ergo_lib_tx_builder_output_candidates(t.p, &p)
 	bc := &boxCandidates{p: p}
@@ -98,11 +151,25 @@ func (t *txBuilder) OutputCandidates() BoxCandidates {
 }
 
 func (t *txBuilder) CurrentHeight() uint32 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	res := C.ergo_lib_tx_builder_current_height(t.p)
 	return uint32(res)
 }
 
 func (t *txBuilder) FeeAmount() BoxValue {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.BoxValuePtr
 	C.ergo_lib_tx_builder_fee_amount(t.p, &p)
 	bv := &boxValue{p: p}
@@ -110,6 +177,13 @@ func (t *txBuilder) FeeAmount() BoxValue {
 }
 
 func (t *txBuilder) ChangeAddress() Address {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		panic(ErrClosed)
+	}
+
 	var p C.AddressPtr
 	C.ergo_lib_tx_builder_change_address(t.p, &p)
 	a := &address{p: p}
@@ -117,6 +191,13 @@ func (t *txBuilder) ChangeAddress() Address {
 }
 
 func (t *txBuilder) Build() (UnsignedTransaction, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.UnsignedTransactionPtr
 
 	errPtr := C.ergo_lib_tx_builder_build(t.p, &p)
@@ -130,6 +211,17 @@ func (t *txBuilder) Build() (UnsignedTransaction, error) {
 	return newUnsignedTransaction(ut), nil
 }
 
+func (t *txBuilder) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.p != nil {
+		runtime.SetFinalizer(t, nil)
+		finalizeTxBuilder(t)
+		t.p = nil
+	}
+}
+
 func finalizeTxBuilder(t *txBuilder) {
 	C.ergo_lib_tx_builder_delete(t.p)
 }
@@ -6,7 +6,8 @@ package ergo
 import "C"
 import "unsafe"
 
-// VerifySignature verifies that the signature is presented to satisfy SigmaProp conditions
+// VerifySignature verifies that the signature is presented to satisfy SigmaProp conditions.
+// As signatures can only be created by Wallet.SignMessageUsingP2PK, only signatures for P2PK addresses can be verified
 func VerifySignature(address Address, message []byte, signature SignedMessage) (bool, error) {
 	byteData := C.CBytes(message)
 	defer C.free(unsafe.Pointer(byteData))
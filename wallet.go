@@ -5,7 +5,9 @@ package ergo
 */
 import "C"
 import (
+	"context"
 	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -15,6 +17,8 @@ type MnemonicGenerator interface {
 	Generate() (string, error)
 	// GenerateFromEntropy generates a new mnemonic sentence using provided entropy
 	GenerateFromEntropy(entropy []byte) (string, error)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 }
 
 type mnemonicGenerator struct {
@@ -42,6 +46,10 @@ func NewMnemonicGenerator(language string, strength uint32) (MnemonicGenerator,
 }
 
 func (m *mnemonicGenerator) Generate() (string, error) {
+	if m.p == nil {
+		return "", ErrClosed
+	}
+
 	var returnStr C.ReturnString
 
 	returnStr = C.ergo_lib_mnemonic_generator_generate(m.p)
@@ -57,10 +65,14 @@ func (m *mnemonicGenerator) Generate() (string, error) {
 }
 
 func (m *mnemonicGenerator) GenerateFromEntropy(entropy []byte) (string, error) {
+	if m.p == nil {
+		return "", ErrClosed
+	}
+
 	var returnStr C.ReturnString
 
 	byteData := C.CBytes(entropy)
-	defer C.free(unsafe.Pointer(byteData))
+	defer freeSecret(byteData, len(entropy))
 
 	returnStr = C.ergo_lib_mnemonic_generator_generate_from_entropy(m.p, (*C.uchar)(byteData), C.uintptr_t(len(entropy)))
 	defer C.ergo_lib_mnemonic_generator_free_mnemonic(returnStr.value)
@@ -74,6 +86,14 @@ func (m *mnemonicGenerator) GenerateFromEntropy(entropy []byte) (string, error)
 	return mnemonic, nil
 }
 
+func (m *mnemonicGenerator) Close() {
+	if m.p != nil {
+		runtime.SetFinalizer(m, nil)
+		finalizeMnemonicGenerator(m)
+		m.p = nil
+	}
+}
+
 func finalizeMnemonicGenerator(m *mnemonicGenerator) {
 	C.free(unsafe.Pointer(m.p))
 }
@@ -83,22 +103,45 @@ type Wallet interface {
 	AddSecret(secret SecretKey) error
 	// SignTransaction signs a transaction
 	SignTransaction(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error)
+	// SignTransactionContext signs a transaction like SignTransaction, but returns ctx.Err() as soon as ctx is done.
+	// The native signing keeps running after cancellation, closing the Wallet or the arguments waits for it.
+	// The same applies to the other Context variants
+	SignTransactionContext(ctx context.Context, stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error)
 	// SignTransactionMulti signs a multi signature transaction
 	SignTransactionMulti(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes, txHints TransactionHintsBag) (Transaction, error)
+	// SignTransactionMultiContext signs a multi signature transaction like SignTransactionMulti, but returns ctx.Err() as soon as ctx is done
+	SignTransactionMultiContext(ctx context.Context, stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes, txHints TransactionHintsBag) (Transaction, error)
 	// SignReducedTransaction signs a reduced transaction (generating proofs for inputs)
 	SignReducedTransaction(reducedTx ReducedTransaction) (Transaction, error)
+	// SignReducedTransactionContext signs a reduced transaction like SignReducedTransaction, but returns ctx.Err() as soon as ctx is done
+	SignReducedTransactionContext(ctx context.Context, reducedTx ReducedTransaction) (Transaction, error)
 	// SignReducedTransactionMulti signs a multi signature reduced transaction
 	SignReducedTransactionMulti(reducedTx ReducedTransaction, txHints TransactionHintsBag) (Transaction, error)
-	// GenerateCommitments generates Commitments for unsigned tx
+	// SignReducedTransactionMultiContext signs a multi signature reduced transaction like SignReducedTransactionMulti,
+	// but returns ctx.Err() as soon as ctx is done
+	SignReducedTransactionMultiContext(ctx context.Context, reducedTx ReducedTransaction, txHints TransactionHintsBag) (Transaction, error)
+	// GenerateCommitments generates Commitments for unsigned tx. The returned TransactionHintsBag holds the secret
+	// commitments of the wallet next to the public ones and ergo-lib-c cannot tell them apart, so it must not leave
+	// the process. Cosigners in the same process can use MultisigSession, cosigners elsewhere have to receive the
+	// public commitments from the Ergo node, see TransactionHintsBag
 	GenerateCommitments(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (TransactionHintsBag, error)
 	// GenerateCommitmentsForReducedTransaction generates Commitments for reduced transaction
 	GenerateCommitmentsForReducedTransaction(reducedTx ReducedTransaction) (TransactionHintsBag, error)
-	// SignMessageUsingP2PK signs an arbitrary message using a P2PK address
+	// SignMessageUsingP2PK signs an arbitrary message using a P2PK address. ergo-lib-c only signs messages for
+	// P2PK addresses and takes no hints, messages can not be signed for other sigma propositions nor by several parties
 	SignMessageUsingP2PK(address Address, message []byte) (SignedMessage, error)
+	// SetCommitmentStore sets the CommitmentStore used to prevent signing different transactions with the same
+	// commitments. A nil store disables the check, which is the default
+	SetCommitmentStore(store CommitmentStore)
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once.
+	// ergo-lib-c does not zero the secrets of the Wallet when they are freed
+	Close()
 }
 
 type wallet struct {
-	p C.WalletPtr
+	p     C.WalletPtr
+	mu    sync.RWMutex
+	store CommitmentStore
 }
 
 func newWallet(w *wallet) Wallet {
@@ -109,9 +152,9 @@ func newWallet(w *wallet) Wallet {
 // NewWallet creates a Wallet instance loading secret key from mnemonic or throws error if a DlogSecretKey cannot be parsed from the provided phrase
 func NewWallet(mnemonicPhrase string, mnemonicPassword string) (Wallet, error) {
 	mnemonic := C.CString(mnemonicPhrase)
-	defer C.free(unsafe.Pointer(mnemonic))
+	defer freeSecret(unsafe.Pointer(mnemonic), len(mnemonicPhrase))
 	password := C.CString(mnemonicPassword)
-	defer C.free(unsafe.Pointer(password))
+	defer freeSecret(unsafe.Pointer(password), len(mnemonicPassword))
 
 	var p C.WalletPtr
 
@@ -129,6 +172,8 @@ func NewWallet(mnemonicPhrase string, mnemonicPassword string) (Wallet, error) {
 
 // NewWalletFromSecretKeys creates a Wallet from secrets
 func NewWalletFromSecretKeys(secrets SecretKeys) Wallet {
+	defer readLock(secrets)()
+
 	var p C.WalletPtr
 	C.ergo_lib_wallet_from_secrets(secrets.pointer(), &p)
 	w := &wallet{p: p}
@@ -136,6 +181,13 @@ func NewWalletFromSecretKeys(secrets SecretKeys) Wallet {
 }
 
 func (w *wallet) AddSecret(secret SecretKey) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.p == nil {
+		return ErrClosed
+	}
+
 	errPtr := C.ergo_lib_wallet_add_secret(w.p, secret.pointer())
 	err := newError(errPtr)
 	if err.isError() {
@@ -145,6 +197,16 @@ func (w *wallet) AddSecret(secret SecretKey) error {
 }
 
 func (w *wallet) SignTransaction(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error) {
+	defer readLock(w, stateContext, unsignedTx, boxesToSpend, dataBoxes)()
+	return w.signTransaction(stateContext, unsignedTx, boxesToSpend, dataBoxes)
+}
+
+// signTransaction signs with the wallet and the arguments read locked by the caller
+func (w *wallet) signTransaction(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error) {
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.TransactionPtr
 	errPtr := C.ergo_lib_wallet_sign_transaction(w.p, stateContext.pointer(), unsignedTx.pointer(), boxesToSpend.pointer(), dataBoxes.pointer(), &p)
 	err := newError(errPtr)
@@ -156,6 +218,20 @@ func (w *wallet) SignTransaction(stateContext StateContext, unsignedTx UnsignedT
 }
 
 func (w *wallet) SignTransactionMulti(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes, txHints TransactionHintsBag) (Transaction, error) {
+	defer readLock(w, stateContext, unsignedTx, boxesToSpend, dataBoxes, txHints)()
+	return w.signTransactionMulti(stateContext, unsignedTx, boxesToSpend, dataBoxes, txHints)
+}
+
+// signTransactionMulti signs with the wallet and the arguments read locked by the caller
+func (w *wallet) signTransactionMulti(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes, txHints TransactionHintsBag) (Transaction, error) {
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
+	if err := bindCommitments(w.store, txHints.commitmentIds(), unsignedTx.TxId()); err != nil {
+		return nil, err
+	}
+
 	var p C.TransactionPtr
 	errPtr := C.ergo_lib_wallet_sign_transaction_multi(w.p, stateContext.pointer(), unsignedTx.pointer(), boxesToSpend.pointer(), dataBoxes.pointer(), txHints.pointer(), &p)
 	err := newError(errPtr)
@@ -167,6 +243,16 @@ func (w *wallet) SignTransactionMulti(stateContext StateContext, unsignedTx Unsi
 }
 
 func (w *wallet) SignReducedTransaction(reducedTx ReducedTransaction) (Transaction, error) {
+	defer readLock(w, reducedTx)()
+	return w.signReducedTransaction(reducedTx)
+}
+
+// signReducedTransaction signs with the wallet and the arguments read locked by the caller
+func (w *wallet) signReducedTransaction(reducedTx ReducedTransaction) (Transaction, error) {
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.TransactionPtr
 	errPtr := C.ergo_lib_wallet_sign_reduced_transaction(w.p, reducedTx.pointer(), &p)
 	err := newError(errPtr)
@@ -178,6 +264,24 @@ func (w *wallet) SignReducedTransaction(reducedTx ReducedTransaction) (Transacti
 }
 
 func (w *wallet) SignReducedTransactionMulti(reducedTx ReducedTransaction, txHints TransactionHintsBag) (Transaction, error) {
+	defer readLock(w, reducedTx, txHints)()
+	return w.signReducedTransactionMulti(reducedTx, txHints)
+}
+
+// signReducedTransactionMulti signs with the wallet and the arguments read locked by the caller
+func (w *wallet) signReducedTransactionMulti(reducedTx ReducedTransaction, txHints TransactionHintsBag) (Transaction, error) {
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
+	if w.store != nil {
+		unsignedTx := reducedTx.UnsignedTransaction()
+		defer unsignedTx.Close()
+		if err := bindCommitments(w.store, txHints.commitmentIds(), unsignedTx.TxId()); err != nil {
+			return nil, err
+		}
+	}
+
 	var p C.TransactionPtr
 	errPtr := C.ergo_lib_wallet_sign_reduced_transaction_multi(w.p, reducedTx.pointer(), txHints.pointer(), &p)
 	err := newError(errPtr)
@@ -188,7 +292,39 @@ func (w *wallet) SignReducedTransactionMulti(reducedTx ReducedTransaction, txHin
 	return newTransaction(t), nil
 }
 
+func (w *wallet) SignTransactionContext(ctx context.Context, stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (Transaction, error) {
+	return withContext(ctx, []locker{w, stateContext, unsignedTx, boxesToSpend, dataBoxes}, func() (Transaction, error) {
+		return w.signTransaction(stateContext, unsignedTx, boxesToSpend, dataBoxes)
+	}, closeTransaction)
+}
+
+func (w *wallet) SignTransactionMultiContext(ctx context.Context, stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes, txHints TransactionHintsBag) (Transaction, error) {
+	return withContext(ctx, []locker{w, stateContext, unsignedTx, boxesToSpend, dataBoxes, txHints}, func() (Transaction, error) {
+		return w.signTransactionMulti(stateContext, unsignedTx, boxesToSpend, dataBoxes, txHints)
+	}, closeTransaction)
+}
+
+func (w *wallet) SignReducedTransactionContext(ctx context.Context, reducedTx ReducedTransaction) (Transaction, error) {
+	return withContext(ctx, []locker{w, reducedTx}, func() (Transaction, error) {
+		return w.signReducedTransaction(reducedTx)
+	}, closeTransaction)
+}
+
+func (w *wallet) SignReducedTransactionMultiContext(ctx context.Context, reducedTx ReducedTransaction, txHints TransactionHintsBag) (Transaction, error) {
+	return withContext(ctx, []locker{w, reducedTx, txHints}, func() (Transaction, error) {
+		return w.signReducedTransactionMulti(reducedTx, txHints)
+	}, closeTransaction)
+}
+
 func (w *wallet) GenerateCommitments(stateContext StateContext, unsignedTx UnsignedTransaction, boxesToSpend Boxes, dataBoxes Boxes) (TransactionHintsBag, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	defer readLock(boxesToSpend, dataBoxes)()
+
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.TransactionHintsBagPtr
 	errPtr := C.ergo_lib_wallet_generate_commitments(w.p, stateContext.pointer(), unsignedTx.pointer(), boxesToSpend.pointer(), dataBoxes.pointer(), &p)
 	err := newError(errPtr)
@@ -196,10 +332,17 @@ func (w *wallet) GenerateCommitments(stateContext StateContext, unsignedTx Unsig
 		return nil, err.error()
 	}
 	th := &transactionHintsBag{p: p}
-	return newTransactionHintsBag(th), nil
+	return w.bindGeneratedCommitments(newTransactionHintsBag(th), unsignedTx)
 }
 
 func (w *wallet) GenerateCommitmentsForReducedTransaction(reducedTx ReducedTransaction) (TransactionHintsBag, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
 	var p C.TransactionHintsBagPtr
 	errPtr := C.ergo_lib_wallet_generate_commitments_for_reduced_transaction(w.p, reducedTx.pointer(), &p)
 	err := newError(errPtr)
@@ -207,10 +350,41 @@ func (w *wallet) GenerateCommitmentsForReducedTransaction(reducedTx ReducedTrans
 		return nil, err.error()
 	}
 	th := &transactionHintsBag{p: p}
-	return newTransactionHintsBag(th), nil
+	unsignedTx := reducedTx.UnsignedTransaction()
+	defer unsignedTx.Close()
+	return w.bindGeneratedCommitments(newTransactionHintsBag(th), unsignedTx)
+}
+
+// bindGeneratedCommitments marks txHints with a new commitment id bound to unsignedTx in the CommitmentStore
+func (w *wallet) bindGeneratedCommitments(txHints TransactionHintsBag, unsignedTx UnsignedTransaction) (TransactionHintsBag, error) {
+	if w.store == nil {
+		return txHints, nil
+	}
+
+	id := newCommitmentId()
+	if err := bindCommitments(w.store, []string{id}, unsignedTx.TxId()); err != nil {
+		txHints.Close()
+		return nil, err
+	}
+	txHints.(*transactionHintsBag).commitments = []string{id}
+	return txHints, nil
+}
+
+func (w *wallet) SetCommitmentStore(store CommitmentStore) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.store = store
 }
 
 func (w *wallet) SignMessageUsingP2PK(address Address, message []byte) (SignedMessage, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.p == nil {
+		return nil, ErrClosed
+	}
+
 	byteData := C.CBytes(message)
 	defer C.free(unsafe.Pointer(byteData))
 
@@ -224,11 +398,36 @@ func (w *wallet) SignMessageUsingP2PK(address Address, message []byte) (SignedMe
 	return newSignedMessage(sm), nil
 }
 
+func (w *wallet) rlock() {
+	w.mu.RLock()
+}
+
+func (w *wallet) runlock() {
+	w.mu.RUnlock()
+}
+
+func (w *wallet) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.p != nil {
+		runtime.SetFinalizer(w, nil)
+		finalizeWallet(w)
+		w.p = nil
+	}
+}
+
 func finalizeWallet(w *wallet) {
 	C.ergo_lib_wallet_delete(w.p)
 }
 
+// SignedMessage is a signature of a message created by Wallet.SignMessageUsingP2PK.
+//
+// ergo-lib-c neither exports the bytes of a SignedMessage nor creates one from bytes, so a signature can only be
+// verified with VerifySignature in the process that created it and can not be sent to or received from other wallets.
 type SignedMessage interface {
+	// Close frees the underlying native memory immediately. It is safe to call Close more than once
+	Close()
 	pointer() C.SignedMessagePtr
 }
 
@@ -242,9 +441,21 @@ func newSignedMessage(s *signedMessage) SignedMessage {
 }
 
 func (s *signedMessage) pointer() C.SignedMessagePtr {
+	if s.p == nil {
+		panic(ErrClosed)
+	}
+
 	return s.p
 }
 
+func (s *signedMessage) Close() {
+	if s.p != nil {
+		runtime.SetFinalizer(s, nil)
+		finalizeSignedMessage(s)
+		s.p = nil
+	}
+}
+
 func finalizeSignedMessage(s *signedMessage) {
 	C.ergo_lib_signed_message_delete(s.p)
 }
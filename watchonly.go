@@ -0,0 +1,173 @@
+package ergo
+
+import "math"
+
+// WatchOnlyAccount derives the addresses of an EIP-3 account from the ExtendedPublicKey of the account, see
+// NewExtendedPublicKeyFromBase58, and builds transactions to be signed by an offline signer, e.g. an EIP-19 cold
+// wallet or an ErgoPay wallet. It holds no secrets.
+// Addresses are derived with Address and NextAddress, MarkUsed, Used and Discover keep track of the used ones
+type WatchOnlyAccount struct {
+	addressBook
+	// chain is the key of the external chain at m/44'/429'/account'/0, the addresses are its children
+	chain ExtendedPublicKey
+}
+
+// NewWatchOnlyAccount creates the WatchOnlyAccount of the account with accountKey, the key at m/44'/429'/account'
+func NewWatchOnlyAccount(accountKey ExtendedPublicKey) (*WatchOnlyAccount, error) {
+	chain, err := accountKey.Child(0)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WatchOnlyAccount{chain: chain}
+	w.addressBook = newAddressBook(w.addressAt)
+	return w, nil
+}
+
+// BuildTransaction selects inputs out of boxes to create outputs and pay fee and builds the UnsignedTransaction,
+// the change goes to NextAddress. boxes must be guarded by addresses of the account derived before.
+// It returns the selected inputs as well, they are needed to reduce or sign the transaction
+func (w *WatchOnlyAccount) BuildTransaction(boxes Boxes, outputs BoxCandidates, currentHeight uint32, fee BoxValue) (UnsignedTransaction, Boxes, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// the offline signer only holds the keys of the account
+	for _, box := range boxes.All() {
+		_, err := w.boxIndex(box)
+		box.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	targetBalance, targetTokens, err := outputTotals(outputs, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer targetBalance.Close()
+	defer targetTokens.Close()
+
+	selector := NewSimpleBoxSelector()
+	defer selector.Close()
+	selection, err := selector.Select(boxes, targetBalance, targetTokens)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer selection.Close()
+
+	changeAddress, _, err := w.nextAddress()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer changeAddress.Close()
+
+	builder := NewTxBuilder(selection, outputs, currentHeight, fee, changeAddress)
+	defer builder.Close()
+	unsignedTx, err := builder.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	return unsignedTx, selection.Boxes(), nil
+}
+
+// BuildReducedTransaction builds the transaction like BuildTransaction and reduces it for an offline signer
+func (w *WatchOnlyAccount) BuildReducedTransaction(stateContext StateContext, boxes Boxes, outputs BoxCandidates, currentHeight uint32, fee BoxValue) (ReducedTransaction, error) {
+	unsignedTx, inputs, err := w.BuildTransaction(boxes, outputs, currentHeight, fee)
+	if err != nil {
+		return nil, err
+	}
+	defer unsignedTx.Close()
+	defer inputs.Close()
+
+	dataBoxes := NewBoxes()
+	defer dataBoxes.Close()
+	return NewReducedTransaction(unsignedTx, inputs, dataBoxes, stateContext)
+}
+
+// Close frees the underlying native memory immediately. It is safe to call Close more than once
+func (w *WatchOnlyAccount) Close() {
+	w.chain.Close()
+}
+
+// addressAt derives the address at index
+func (w *WatchOnlyAccount) addressAt(index uint32) (Address, error) {
+	key, err := w.chain.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Close()
+	return key.Address(), nil
+}
+
+// outputTotals returns the value and the tokens needed to create outputs and pay fee
+func outputTotals(outputs BoxCandidates, fee BoxValue) (BoxValue, Tokens, error) {
+	value, err := Sum(outputs, func(c BoxCandidate) int64 {
+		defer c.Close()
+		boxValue := c.BoxValue()
+		defer boxValue.Close()
+		return boxValue.Int64()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	outputsValue, err := NewBoxValue(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer outputsValue.Close()
+	total, err := SumOfBoxValues(outputsValue, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// token amounts by token id, in the order of their first occurrence
+	var tokenIds []string
+	amounts := make(map[string]int64)
+	overflow := false
+	for _, candidate := range outputs.All() {
+		candidateTokens := candidate.Tokens()
+		for _, t := range candidateTokens.All() {
+			tokenId := t.Id()
+			amount := t.Amount()
+			id := tokenId.Base16()
+			if _, ok := amounts[id]; !ok {
+				tokenIds = append(tokenIds, id)
+			}
+			overflow = overflow || amounts[id] > math.MaxInt64-amount.Int64()
+			amounts[id] += amount.Int64()
+			tokenId.Close()
+			amount.Close()
+			t.Close()
+		}
+		candidateTokens.Close()
+		candidate.Close()
+	}
+
+	if overflow {
+		total.Close()
+		return nil, nil, errSumOverflow
+	}
+
+	tokens := NewTokens()
+	for _, id := range tokenIds {
+		tokenId, err := NewTokenId(id)
+		if err != nil {
+			total.Close()
+			tokens.Close()
+			return nil, nil, err
+		}
+		amount, err := NewTokenAmount(amounts[id])
+		if err != nil {
+			tokenId.Close()
+			total.Close()
+			tokens.Close()
+			return nil, nil, err
+		}
+		token := NewToken(tokenId, amount)
+		tokens.Add(token)
+		token.Close()
+		tokenId.Close()
+		amount.Close()
+	}
+	return total, tokens, nil
+}
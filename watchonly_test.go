@@ -0,0 +1,54 @@
+package ergo
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWatchOnlyAccount(t *testing.T) {
+	seed := MnemonicToSeed("chef hidden swift slush bar length outdoor pupil hunt country endorse accuse", "")
+	master, _ := DeriveMaster(seed)
+	accountPath, _ := NewDerivationPathFromString("m/44'/429'/0'")
+	accountKey, _ := master.Derive(accountPath)
+
+	encoded, err := accountKey.ExtendedPublicKey().Base58(MainnetPrefix)
+	assert.NoError(t, err)
+	publicKey, err := NewExtendedPublicKeyFromBase58(encoded)
+	assert.NoError(t, err)
+	account, err := NewWatchOnlyAccount(publicKey)
+	assert.NoError(t, err)
+	defer account.Close()
+
+	assert.NoError(t, account.Discover(2, func(address Address) (bool, error) {
+		return address.Base58(MainnetPrefix) == "9hRTUYF37avZvhC5FG7VoSfrfWQgRMubrA4xLqwFBfes743691r", nil
+	}))
+	assert.Equal(t, []uint32{0}, account.Used())
+
+	first, _ := account.Address(0)
+	change, index, err := account.NextAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), index)
+	assert.Equal(t, "9gYRhhA9TcFv6xWGwTBPLBJzyW1Hv3EiDzXqoivWYjq8TowWJ1h", change.Base58(MainnetPrefix))
+
+	boxes := NewBoxes()
+	boxes.Add(testAccountBox(t, first))
+	recipient, _ := NewAddress("9gHMTduN2xseqb5NMKQtNSeS7Pe6wm7AwGoLoMERidWDERQunvn")
+	recipientContract, _ := NewContractPayToAddress(recipient)
+	outputValue, _ := NewBoxValue(100000000)
+	outputs := NewBoxCandidates()
+	candidate, err := NewBoxCandidateBuilder(outputValue, recipientContract, 284761).Build()
+	assert.NoError(t, err)
+	outputs.Add(candidate)
+	fee, _ := NewBoxValue(1100000)
+
+	unsignedTx, inputs, err := account.BuildTransaction(boxes, outputs, 284761, fee)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inputs.Len())
+	// the outputs, the change to the next address and the fee
+	assert.Equal(t, 3, unsignedTx.OutputCandidates().Len())
+
+	foreignBoxes := NewBoxes()
+	foreignBoxes.Add(testAccountBox(t, recipient))
+	_, _, err = account.BuildTransaction(foreignBoxes, outputs, 284761, fee)
+	assert.Error(t, err)
+}
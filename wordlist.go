@@ -0,0 +1,45 @@
+package ergo
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// the BIP-39 word lists of the languages supported by NewMnemonicGenerator, the words are NFKD normalized
+//
+//go:embed wordlists/*.txt
+var wordlistFiles embed.FS
+
+type wordlist struct {
+	words []string
+	// index maps a word to its position in words
+	index map[string]int
+}
+
+var (
+	wordlistsMu sync.Mutex
+	wordlists   = make(map[string]*wordlist)
+)
+
+// loadWordlist returns the word list of language, e.g. english or chinese_simplified
+func loadWordlist(language string) (*wordlist, error) {
+	wordlistsMu.Lock()
+	defer wordlistsMu.Unlock()
+
+	if w, ok := wordlists[language]; ok {
+		return w, nil
+	}
+	data, err := wordlistFiles.ReadFile("wordlists/" + language + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("unsupported mnemonic language %q", language)
+	}
+
+	w := &wordlist{words: strings.Fields(string(data)), index: make(map[string]int)}
+	for i, word := range w.words {
+		w.index[word] = i
+	}
+	wordlists[language] = w
+	return w, nil
+}